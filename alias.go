@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// applyAliases attaches configured aliases to catalog commands, keyed by
+// command name. It returns the alias to subcommand mapping used to expand
+// raw commands, plus warnings for entries that were skipped.
+func applyAliases(cats []commandCategory, aliases map[string][]string) (map[string][]string, []string) {
+	var warnings []string
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+
+	builtin := map[string]bool{}
+	for _, c := range cats {
+		for _, item := range c.commands {
+			if len(item.baseArgs) > 0 {
+				builtin[item.baseArgs[0]] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	expand := map[string][]string{}
+	for _, name := range names {
+		item := findCommand(cats, name)
+		if item == nil || len(item.baseArgs) == 0 {
+			warnings = append(warnings, fmt.Sprintf("aliases: unknown command %q ignored", name))
+			continue
+		}
+		for _, alias := range aliases[name] {
+			alias = strings.ToLower(strings.TrimSpace(alias))
+			switch {
+			case alias == "" || strings.ContainsAny(alias, " \t"):
+				warnings = append(warnings, fmt.Sprintf("aliases.%s: %q must be a single word", name, alias))
+			case builtin[alias]:
+				warnings = append(warnings, fmt.Sprintf("aliases.%s: %q is already a devtunnel command", name, alias))
+			case expand[alias] != nil:
+				warnings = append(warnings, fmt.Sprintf("aliases.%s: %q is already used", name, alias))
+			default:
+				expand[alias] = item.baseArgs
+				item.aliases = append(item.aliases, alias)
+			}
+		}
+	}
+	return expand, warnings
+}
+
+// findCommand returns the catalog entry with the given name, ignoring case.
+func findCommand(cats []commandCategory, name string) *commandItem {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for ci := range cats {
+		for j := range cats[ci].commands {
+			if strings.ToLower(cats[ci].commands[j].name) == name {
+				return &cats[ci].commands[j]
+			}
+		}
+	}
+	return nil
+}
+
+// expandAlias replaces a leading alias in raw command fields with the
+// subcommand it stands for.
+func (m model) expandAlias(fields []string) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	target, ok := m.aliases[strings.ToLower(fields[0])]
+	if !ok {
+		return fields
+	}
+	return append(append([]string{}, target...), fields[1:]...)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// authFailureRe matches the ways devtunnel reports missing or expired
+// credentials. It keys on whole messages rather than words like "login"
+// or "401", which also turn up in help text, tunnel names and port numbers;
+// a bare "Unauthorized" only counts at the start of a line, as the CLI
+// prints it.
+var authFailureRe = regexp.MustCompile(`(?im)(\bnot logged in\b|\blogin required\b|\bplease (log|sign) ?in\b|\b(access )?token (has )?expired\b|\bcredentials? (have |has )?expired\b|\bexpired (token|credentials?)\b|\b401 unauthori[sz]ed\b|^\s*(error:\s*)?unauthori[sz]ed([.:!,]|\s|$))`)
+
+// isAuthFailure reports whether a failed run looks like an auth problem.
+func isAuthFailure(msg runFinishedMsg) bool {
+	return msg.err != nil && authFailureRe.MatchString(msg.output)
+}
+
+type loginFinishedMsg struct{ err error }
+
+// loginCmd hands the terminal to `devtunnel user login` so its prompts and
+// browser hand-off work, then returns to the TUI.
+func (m model) loginCmd() tea.Cmd {
+	c := exec.Command(m.procs.program("devtunnel"), "user", "login")
+	c.Dir = m.workDir
+	return tea.ExecProcess(c, func(err error) tea.Msg { return loginFinishedMsg{err: err} })
+}
+
+func (m model) updateLoginPrompt(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "y", "enter":
+		m.loginPrompt = false
+		m.statusErr = false
+		m.statusText = "running devtunnel user login"
+		return m, m.loginCmd()
+	case "n", "esc", "q":
+		m.loginPrompt = false
+	}
+	return m, nil
+}
+
+func (m model) onLoginFinished(msg loginFinishedMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusErr = true
+		m.statusText = "login failed: " + msg.err.Error()
+		return m, nil
+	}
+	m.statusErr = false
+	m.statusText = "logged in (r to rerun)"
+	return m, m.fetchLoginCmd()
+}
+
+func (m model) renderLoginPrompt() string {
+	return m.styles.shellLine.Render("Credentials look expired or missing. Run `devtunnel user login` now? (y/Enter login, n/Esc dismiss)")
+}
+
+type loginState int
+
+const (
+	loginUnknown loginState = iota
+	loginYes
+	loginNo
+)
+
+type loginStateMsg struct {
+	state loginState
+	user  string
+}
+
+var loggedInAsRe = regexp.MustCompile(`(?i)logged in as (\S+)`)
+
+// parseLoginState reads `devtunnel user show` output.
+func parseLoginState(output string) (loginState, string) {
+	if m := loggedInAsRe.FindStringSubmatch(output); m != nil {
+		return loginYes, m[1]
+	}
+	if strings.Contains(strings.ToLower(output), "not logged in") {
+		return loginNo, ""
+	}
+	return loginUnknown, ""
+}
+
+func (m model) fetchLoginCmd() tea.Cmd {
+	procs := m.procs
+	return func() tea.Msg {
+		out, _ := runQuiet(procs, "devtunnel", "user", "show")
+		state, user := parseLoginState(out)
+		return loginStateMsg{state: state, user: user}
+	}
+}
+
+// isLogout reports whether cmd is the catalog's logout entry.
+func isLogout(cmd commandItem) bool {
+	return strings.Join(cmd.baseArgs, " ") == "user logout"
+}
+
+func (m model) updateLogoutPrompt(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "y", "enter", "k":
+		m.logoutPrompt = false
+		m.logoutClear = k.String() != "k"
+		parts := []string{"devtunnel", "user", "logout"}
+		m.lastCmd = parts
+		return m, m.runCommandCmd(parts)
+	case "n", "esc", "q":
+		m.logoutPrompt = false
+		m.statusErr = false
+		m.statusText = "logout cancelled"
+	}
+	return m, nil
+}
+
+// afterLogout marks the session signed out and, if chosen, forgets data
+// fetched with the old credentials.
+func (m model) afterLogout() model {
+	m.login = loginNo
+	m.loginUser = ""
+	m.statusErr = false
+	m.statusText = "logged out"
+	if m.logoutClear {
+		for k := range m.cache {
+			delete(m.cache, k)
+		}
+		m.defaultTunnel = ""
+		m.haveCount = false
+		m.tunnelCount = 0
+		m.statusText = "logged out; cached tunnel data cleared"
+	}
+	return m
+}
+
+func (m model) renderLogoutPrompt() string {
+	return m.styles.shellLine.Render("Log out of devtunnel? y/Enter log out and clear cached tunnel data, k log out but keep it, n/Esc cancel")
+}
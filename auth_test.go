@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsAuthFailure(t *testing.T) {
+	failed := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   bool
+	}{
+		{"not logged in", "Error: Not logged in.", failed, true},
+		{"login required", "Login required. Run 'devtunnel user login' and try again.", failed, true},
+		{"unauthorized", "Unauthorized", failed, true},
+		{"unauthorized with prefix", "Connecting...\nError: Unauthorized.", failed, true},
+		{"http status", "Request failed: 401 Unauthorized", failed, true},
+		{"expired token", "Your access token has expired.", failed, true},
+		{"expired credentials", "Credentials expired, sign in again", failed, true},
+		{"succeeded", "Error: Not logged in.", nil, false},
+		{"help text", "Usage:\n  devtunnel user login [options]", failed, false},
+		{"port number", "Port 401 is already in use", failed, false},
+		{"longer number", "listening on :8401 failed", failed, false},
+		{"tunnel name", "Error: tunnel 'unauthorized-demo' not found", failed, false},
+		{"word mid-sentence", "Tunnel access is unauthorized for anonymous clients: use --allow-anonymous", failed, false},
+		{"unrelated failure", "Tunnel not found", failed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := runFinishedMsg{output: tt.output, err: tt.err}
+			if got := isAuthFailure(msg); got != tt.want {
+				t.Errorf("isAuthFailure(%q) = %v, want %v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
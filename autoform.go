@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autoOpenForm opens the form of the selected command as soon as the
+// selection lands on it when autoForm is set and the command has required
+// fields, saving the Enter.
+func (m model) autoOpenForm() (tea.Model, tea.Cmd) {
+	cmds := m.visibleCommands()
+	if !m.cfg.AutoForm || m.cmdIdx >= len(cmds) {
+		return m, nil
+	}
+	cmd := cmds[m.cmdIdx]
+	if len(cmd.required) == 0 || len(cmd.wizard) > 0 || (m.safeMode && cmd.destructive) || !m.devtunnelFound {
+		return m, nil
+	}
+	next, c := m.runItem(cmd)
+	if nm, ok := next.(model); ok && nm.formMode {
+		nm.formAuto = true
+		return nm, c
+	}
+	return next, c
+}
+
+// formUntouched reports whether nothing has been typed or chosen in the
+// form yet.
+func (m model) formUntouched() bool {
+	for _, in := range m.formInputs {
+		if strings.TrimSpace(in.Value()) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// leaveAutoForm lets ↑/↓ keep browsing the list from a form that opened by
+// itself and has not been used yet.
+func (m model) leaveAutoForm(k tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if !m.formAuto || !m.formUntouched() || (k.Type != tea.KeyUp && k.Type != tea.KeyDown) {
+		return m, nil, false
+	}
+	m = m.closeForm()
+	if k.Type == tea.KeyUp {
+		m = m.moveUp()
+	} else {
+		m = m.moveDown()
+	}
+	next, cmd := m.autoOpenForm()
+	return next, cmd, true
+}
+
+// confirmNoArgs asks before running a command that takes no input, when
+// confirmNoArgs is set, since nothing else stands between Enter and the run.
+func (m model) confirmNoArgs(cmd *commandItem) (model, bool) {
+	if !m.cfg.ConfirmNoArgs {
+		return m, false
+	}
+	m.confirmMode = true
+	m.confirmArgs = m.withClusterArgs(cmd, commandArgs(cmd))
+	return m, true
+}
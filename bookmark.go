@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bookmark is a named scroll position in the current result. Bookmarks are
+// dropped whenever the output is replaced by a new result.
+type bookmark struct {
+	name   string
+	offset int
+}
+
+func (m model) openBookmarkPrompt() (tea.Model, tea.Cmd) {
+	if m.lastText == "" {
+		m.statusErr = true
+		m.statusText = "no output to bookmark"
+		return m, nil
+	}
+	return m.openPrompt(promptBookmark, "bookmark name", fmt.Sprintf("line %d", m.viewport.YOffset+1))
+}
+
+// addBookmark records the current scroll position under name, replacing a
+// bookmark with the same name.
+func (m model) addBookmark(name string) model {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fmt.Sprintf("line %d", m.viewport.YOffset+1)
+	}
+	bm := bookmark{name: name, offset: m.viewport.YOffset}
+	replaced := false
+	for i := range m.bookmarks {
+		if m.bookmarks[i].name == name {
+			m.bookmarks[i] = bm
+			replaced = true
+		}
+	}
+	if !replaced {
+		m.bookmarks = append(m.bookmarks, bm)
+	}
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("bookmark %q at line %d (' to jump)", name, bm.offset+1)
+	return m
+}
+
+func (m model) openBookmarkPicker() model {
+	if len(m.bookmarks) == 0 {
+		m.statusErr = true
+		m.statusText = "no bookmarks in this output (M to add)"
+		return m
+	}
+	items := make([]string, len(m.bookmarks))
+	for i, bm := range m.bookmarks {
+		items[i] = fmt.Sprintf("%s  (line %d)", bm.name, bm.offset+1)
+	}
+	return m.openPicker(pickBookmark, "Jump to bookmark", items, len(items)-1)
+}
+
+func (m model) jumpToBookmark(idx int) model {
+	bm := m.bookmarks[idx]
+	m.viewport.SetYOffset(bm.offset)
+	m.outCursor = min(bm.offset, len(m.outLines)-1)
+	m.statusErr = false
+	m.statusText = "bookmark " + bm.name
+	return m
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type cacheEntry struct {
+	output string
+	stdout string
+	stderr string
+	at     time.Time
+}
+
+// resultCache holds outputs of read-only commands keyed by their argv.
+type resultCache map[string]cacheEntry
+
+func cacheKey(parts []string) string {
+	return strings.Join(parts, "\x00")
+}
+
+// lookupCommand finds the catalog entry whose baseArgs best match the
+// subcommand in parts, or nil when nothing matches.
+func (m model) lookupCommand(parts []string) *commandItem {
+	if len(parts) < 2 {
+		return nil
+	}
+	args := parts[1:]
+	var best *commandItem
+	for ci := range m.categories {
+		for i := range m.categories[ci].commands {
+			c := &m.categories[ci].commands[i]
+			n := len(c.baseArgs)
+			if n == 0 || n > len(args) || (best != nil && n <= len(best.baseArgs)) {
+				continue
+			}
+			if strings.Join(c.baseArgs, " ") == strings.Join(args[:n], " ") {
+				best = c
+			}
+		}
+	}
+	return best
+}
+
+func (m model) cacheable(parts []string) bool {
+	c := m.lookupCommand(parts)
+	return c != nil && c.cacheable
+}
+
+// recordCache stores successful read-only results and drops everything
+// once a command that may change state has run.
+func (m model) recordCache(msg runFinishedMsg) {
+	if m.cfg.CacheTTLSeconds <= 0 || len(msg.args) == 0 {
+		return
+	}
+	if !m.cacheable(msg.args) {
+		for k := range m.cache {
+			delete(m.cache, k)
+		}
+		return
+	}
+	if msg.err == nil {
+		m.cache[cacheKey(msg.args)] = cacheEntry{output: msg.output, stdout: msg.stdout, stderr: msg.stderr, at: time.Now()}
+	}
+}
+
+// sourceTitle marks in the output title whether the result shown came from
+// the cache, and how old it is, or from a live run.
+func (m model) sourceTitle() string {
+	switch {
+	case m.running || m.viewingJob != 0 || m.outNotLatest || m.lastText == "":
+		return ""
+	case !m.cachedAt.IsZero():
+		return m.styles.warn.Render(" [cached " + agoText(time.Since(m.cachedAt)) + ", F runs live]")
+	default:
+		return m.styles.dim.Render(" [live]")
+	}
+}
+
+// runCached serves parts from the cache when a fresh entry exists and
+// otherwise runs the command.
+func (m model) runCached(parts []string) (tea.Model, tea.Cmd) {
+	ttl := time.Duration(m.cfg.CacheTTLSeconds) * time.Second
+	entry, ok := m.cache[cacheKey(parts)]
+	if !ok || ttl <= 0 || time.Since(entry.at) > ttl {
+		return m, m.runCommandCmd(parts)
+	}
+
+	cmdText := m.secrets.displayCmd(parts)
+	age := time.Since(entry.at).Round(time.Second)
+	m.lastOutput = entry.output
+	m.lastStdout = entry.stdout
+	m.lastStderr = entry.stderr
+	m.haveStreams = true
+	m.outStream = streamCombined
+	m.lastText = cmdText
+	m.cachedAt = entry.at
+	m.failedCmd = nil
+	m.bookmarks = nil
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("cached %s ago (F to refresh)", age)
+	m = m.setOutput("$ " + cmdText + "  [cached " + age.String() + " ago]\n\n" + m.formatOutput(cmdText, entry.output))
+	m.viewport.GotoTop()
+	return m, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// captureTarget picks the token under the output cursor to carry into the
+// next command: the selected link, the tunnel id of a list row, or else
+// the first word of the line.
+func (m model) captureTarget() string {
+	if m.linkIdx >= 0 && m.linkIdx < len(m.links) && m.links[m.linkIdx].line == m.outCursor {
+		return m.links[m.linkIdx].url
+	}
+	line := strings.TrimSpace(m.cursorText())
+	if sm := listTunnelRe.FindStringSubmatch(line); sm != nil {
+		return sm[1]
+	}
+	if f := strings.Fields(line); len(f) > 0 {
+		return f[0]
+	}
+	return ""
+}
+
+// captureToken remembers the token under the output cursor for Ctrl+O in
+// forms and command mode.
+func (m model) captureToken() model {
+	tok := m.captureTarget()
+	if tok == "" {
+		m.statusErr = true
+		m.statusText = "nothing to capture on line " + strconv.Itoa(m.outCursor+1)
+		return m
+	}
+	m.captured = tok
+	m.statusErr = false
+	m.statusText = "captured " + tok + " (Ctrl+O inserts it in a form field or command mode)"
+	return m
+}
+
+// insertCaptured puts the captured token at the input's cursor and reports
+// whether there was one.
+func (m model) insertCaptured(in *textinput.Model) bool {
+	if m.captured == "" {
+		return false
+	}
+	value, pos := insertSnippet(in.Value(), in.Position(), m.captured)
+	in.SetValue(value)
+	in.SetCursor(pos)
+	return true
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// multiField is a form field where one or more options are picked from a
+// fixed set. Each selected option is passed as "<flag> <option>", or as a
+// bare positional argument when flag is empty. A single field picks exactly
+// one option and starts on the first.
+type multiField struct {
+	label   string
+	flag    string
+	options []string
+	single  bool
+}
+
+func newChecklist(f *multiField) checklist {
+	c := checklist{field: f, checked: make([]bool, len(f.options))}
+	if f.single && len(c.checked) > 0 {
+		c.checked[0] = true
+	}
+	return c
+}
+
+// checklist is the form state of a multiField; a zero checklist marks a
+// plain text field.
+type checklist struct {
+	field   *multiField
+	checked []bool
+	cursor  int
+}
+
+func (c checklist) selected() []string {
+	var out []string
+	for i, on := range c.checked {
+		if on {
+			out = append(out, c.field.options[i])
+		}
+	}
+	return out
+}
+
+// update handles keys for a checklist field. It reports false for
+// keys the form should handle itself (submit, cancel, field navigation).
+func (c checklist) update(k tea.KeyMsg) (checklist, bool) {
+	switch k.String() {
+	case "left", "h":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+		if c.field.single {
+			c = c.choose()
+		}
+	case "right", "l":
+		if c.cursor < len(c.field.options)-1 {
+			c.cursor++
+		}
+		if c.field.single {
+			c = c.choose()
+		}
+	case " ", "x":
+		if c.field.single {
+			c = c.choose()
+			break
+		}
+		c.checked[c.cursor] = !c.checked[c.cursor]
+	case "enter", "esc", "tab", "shift+tab", "up", "down", "ctrl+r", "ctrl+y":
+		return c, false
+	}
+	return c, true
+}
+
+// choose makes the option under the cursor the only selected one.
+func (c checklist) choose() checklist {
+	c.checked = make([]bool, len(c.checked))
+	c.checked[c.cursor] = true
+	return c
+}
+
+func (m model) renderChecklist(c checklist) string {
+	items := make([]string, len(c.field.options))
+	for i, opt := range c.field.options {
+		box := "[ ] "
+		if c.checked[i] {
+			box = "[x] "
+		}
+		if c.field.single {
+			box = "( ) "
+			if c.checked[i] {
+				box = "(*) "
+			}
+		}
+		item := box + opt
+		if i == c.cursor {
+			item = m.styles.hotkey.Render("> " + item)
+		} else {
+			item = "  " + item
+		}
+		items[i] = item
+	}
+	return strings.Join(items, "  ")
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/atotto/clipboard"
+	"github.com/muesli/termenv"
+)
+
+// copyToClipboard writes text to the system clipboard. When no clipboard
+// tool is available it falls back to an OSC 52 escape, which most modern
+// terminals and tmux honor; that path cannot report failure.
+func copyToClipboard(text string) {
+	if err := clipboard.WriteAll(text); err != nil {
+		termenv.Copy(text)
+	}
+}
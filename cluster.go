@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clusterFlag is appended with the active cluster to commands that accept it.
+const clusterFlag = "--cluster"
+
+type clustersMsg struct {
+	rows []clusterRow
+	err  error
+}
+
+// clusterRow is one cluster from `devtunnel clusters` and its service URI.
+type clusterRow struct {
+	id  string
+	uri string
+}
+
+// parseClusters extracts clusters from `devtunnel clusters` output. Rows
+// look like "<id>  <uri>"; headers and anything without a URI are skipped.
+func parseClusters(output string) []clusterRow {
+	var rows []clusterRow
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[len(fields)-1], "http") {
+			continue
+		}
+		rows = append(rows, clusterRow{id: fields[0], uri: fields[len(fields)-1]})
+	}
+	return rows
+}
+
+// setClusters remembers the listed clusters for the picker and latency
+// checks.
+func (m model) setClusters(rows []clusterRow) model {
+	m.clusters = nil
+	m.clusterURIs = map[string]string{}
+	for _, r := range rows {
+		m.clusters = append(m.clusters, r.id)
+		m.clusterURIs[r.id] = r.uri
+	}
+	return m
+}
+
+func (m model) fetchClustersCmd() tea.Cmd {
+	procs := m.procs
+	return func() tea.Msg {
+		out, err := runQuiet(procs, "devtunnel", "clusters")
+		if err != nil {
+			return clustersMsg{err: err}
+		}
+		return clustersMsg{rows: parseClusters(out)}
+	}
+}
+
+// openClusterPicker lists the clusters, fastest first once latency has
+// been measured. Items start with the cluster id.
+func (m model) openClusterPicker() model {
+	ids := m.clustersByLatency()
+	items := []string{"(default)"}
+	idx := 0
+	for i, id := range ids {
+		items = append(items, fmt.Sprintf("%-8s %s", id, m.latencyLabel(id)))
+		if id == m.cluster {
+			idx = i + 1
+		}
+	}
+	title := "Select cluster (p measures latency)"
+	if !m.latencyAt.IsZero() {
+		title = fmt.Sprintf("Select cluster (latency %s ago, p to refresh)", time.Since(m.latencyAt).Round(time.Second))
+	}
+	return m.openPicker(pickCluster, title, items, idx)
+}
+
+// withCluster appends the active cluster flag when the command supports it.
+func (m model) withCluster(cmd *commandItem, parts []string) []string {
+	if m.cluster == "" || cmd == nil || !cmd.clusterFlag {
+		return parts
+	}
+	return append(parts, clusterFlag, m.cluster)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// commandLog appends one line per command start and finish to an audit file.
+// The file is opened once at startup; after the first write error logging
+// stops and the error is kept for display instead of interrupting the UI.
+type commandLog struct {
+	path string
+	f    *os.File
+	err  error
+}
+
+// openCommandLog opens path for appending, creating it and its directory as
+// needed, after trimming it to the retention limits. An empty path disables
+// the log and returns nil.
+func openCommandLog(path string, keep logRetention) (*commandLog, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := trimLogFile(path, keep, time.Now()); err != nil {
+		return nil, fmt.Errorf("trimming: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &commandLog{path: path, f: f}, nil
+}
+
+// trimLogFile drops lines older than keep.MaxDays and then the oldest lines
+// until the file fits in keep.MaxKB. Lines without a leading timestamp stay
+// with the entry above them. The file is only rewritten when something was
+// dropped.
+func trimLogFile(path string, keep logRetention, now time.Time) error {
+	if keep.MaxDays <= 0 && keep.MaxKB <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	kept := data
+	if keep.MaxDays > 0 {
+		cutoff := now.AddDate(0, 0, -keep.MaxDays)
+		for len(kept) > 0 {
+			line, rest, _ := bytes.Cut(kept, []byte("\n"))
+			ts, _, _ := strings.Cut(string(line), " ")
+			if at, err := time.Parse(time.RFC3339, ts); err == nil && !at.Before(cutoff) {
+				break
+			}
+			kept = rest
+		}
+	}
+	if limit := keep.MaxKB * 1024; keep.MaxKB > 0 && len(kept) > limit {
+		kept = kept[len(kept)-limit:]
+		if i := bytes.IndexByte(kept, '\n'); i >= 0 {
+			kept = kept[i+1:]
+		}
+	}
+	if len(kept) == len(data) {
+		return nil
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, kept, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *commandLog) active() bool {
+	return l != nil && l.err == nil
+}
+
+// write appends a timestamped line. It reports true only on the write that
+// fails, so the caller can surface the problem once.
+func (l *commandLog) write(event, text string) bool {
+	if !l.active() {
+		return false
+	}
+	line := fmt.Sprintf("%s %-6s %s\n", time.Now().Format(time.RFC3339), event, text)
+	if _, err := l.f.WriteString(line); err != nil {
+		l.err = err
+		return true
+	}
+	return false
+}
+
+func (l *commandLog) close() {
+	if l != nil {
+		l.f.Close()
+	}
+}
+
+// logCommand records a command event and reports a write failure in the
+// status bar.
+func (m model) logCommand(event, text string) model {
+	if m.cmdLog.write(event, text) {
+		m.statusErr = true
+		m.statusText = "command log disabled: " + m.cmdLog.err.Error()
+	}
+	return m
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type notifyConfig struct {
+	Enabled    bool `json:"enabled"`
+	MinSeconds int  `json:"minSeconds"`
+	Desktop    bool `json:"desktop"`
+}
+
+// logRetention bounds the command log; a zero field disables that limit.
+type logRetention struct {
+	MaxKB   int `json:"maxKB"`
+	MaxDays int `json:"maxDays"`
+}
+
+// spinnerConfig picks the bubbles spinner shown while a command runs.
+type spinnerConfig struct {
+	Style      string `json:"style"`
+	IntervalMS int    `json:"intervalMs"` // frame interval, 0 keeps the style's own
+}
+
+type categoryConfig struct {
+	Order  []string `json:"order"`
+	Hidden []string `json:"hidden"`
+}
+
+type config struct {
+	Notify           notifyConfig        `json:"notify"`
+	Workflows        []workflowConfig    `json:"workflows"`
+	Categories       categoryConfig      `json:"categories"`
+	CacheTTLSeconds  int                 `json:"cacheTTLSeconds"`
+	AllowShell       bool                `json:"allowShell"`
+	StartupCommand   string              `json:"startupCommand"`
+	PollSeconds      int                 `json:"pollSeconds"`
+	SetTitle         bool                `json:"setTitle"`
+	WorkDir          string              `json:"workDir"`
+	Aliases          map[string][]string `json:"aliases"`
+	ConfirmArgs      int                 `json:"confirmArgs"`
+	CommandLog       string              `json:"commandLog"`
+	LogRetention     logRetention        `json:"logRetention"`
+	HistorySize      int                 `json:"historySize"`
+	Prefetch         bool                `json:"prefetch"`
+	SafeMode         bool                `json:"safeMode"`
+	AutoForm         bool                `json:"autoForm"`
+	ConfirmNoArgs    bool                `json:"confirmNoArgs"`
+	DiscoverCommands bool                `json:"discoverCommands"`
+	Spinner          spinnerConfig       `json:"spinner"`
+	ReducedMotion    bool                `json:"reducedMotion"`
+	Hooks            map[string]string   `json:"hooks"` // subcommand -> shell run after it succeeds
+	DevtunnelPath    string              `json:"devtunnelPath"`
+	TimeoutMinutes   int                 `json:"timeoutMinutes"`
+
+	CustomCategories []customCategoryConfig `json:"customCategories"`
+}
+
+func defaultConfig() config {
+	return config{
+		Notify:          notifyConfig{MinSeconds: 10},
+		CacheTTLSeconds: 30,
+		SetTitle:        true,
+		LogRetention:    logRetention{MaxKB: 1024, MaxDays: 90},
+		HistorySize:     500,
+		TimeoutMinutes:  10,
+	}
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "devtunnel-tui", "config.json"), nil
+}
+
+// loadConfig reads the JSON config file on top of the built-in defaults.
+// A missing file is not an error.
+func loadConfig() (config, error) {
+	cfg := defaultConfig()
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return defaultConfig(), fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
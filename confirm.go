@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sourcedArg is one argv entry together with where it came from, for the
+// confirmation summary.
+type sourcedArg struct {
+	value  string
+	source string
+}
+
+func argValues(args []sourcedArg) []string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.value
+	}
+	return parts
+}
+
+// commandArgs starts an argv for cmd: the program and its subcommand.
+func commandArgs(cmd *commandItem) []sourcedArg {
+	args := []sourcedArg{{value: "devtunnel", source: "program"}}
+	for _, a := range cmd.baseArgs {
+		args = append(args, sourcedArg{value: a, source: "command"})
+	}
+	return args
+}
+
+// withClusterArgs is withCluster for sourced argv.
+func (m model) withClusterArgs(cmd *commandItem, args []sourcedArg) []sourcedArg {
+	if m.cluster == "" || cmd == nil || !cmd.clusterFlag {
+		return args
+	}
+	return append(args,
+		sourcedArg{value: clusterFlag, source: "selected cluster"},
+		sourcedArg{value: m.cluster, source: "selected cluster"})
+}
+
+// runOrConfirm runs an assembled command, first asking for confirmation
+// when it has at least the configured number of arguments.
+func (m model) runOrConfirm(args []sourcedArg) (tea.Model, tea.Cmd) {
+	if next, asked := m.withForceFlag(args); asked {
+		return next, nil
+	}
+	if n := m.cfg.ConfirmArgs; n > 0 && len(args)-1 >= n {
+		m.confirmMode = true
+		m.confirmArgs = args
+		return m, nil
+	}
+	parts := argValues(args)
+	m.lastCmd = parts
+	return m.runCached(parts)
+}
+
+func (m model) updateConfirm(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "enter":
+		parts := m.forced(argValues(m.confirmArgs))
+		m.confirmMode = false
+		m.confirmArgs = nil
+		m.lastCmd = m.withoutForceFlag(parts)
+		return m.runCached(parts)
+	case "esc", "n", "q":
+		m.nextBackground = false
+		m.confirmMode = false
+		m.confirmArgs = nil
+		m.statusErr = false
+		m.statusText = "cancelled"
+	}
+	return m, nil
+}
+
+func (m model) renderConfirm() string {
+	values := m.secrets.redactArgs(argValues(m.confirmArgs))
+	width := 0
+	for _, v := range values {
+		width = max(width, len(v))
+	}
+	var b strings.Builder
+	b.WriteString(m.styles.warn.Render("Run this command?"))
+	b.WriteString("\n")
+	b.WriteString(strings.Join(values, " "))
+	b.WriteString("\n")
+	for i, a := range m.confirmArgs {
+		b.WriteString(m.styles.dim.Render(fmt.Sprintf("%2d  %-*s  %s", i, width, values[i], a.source)))
+		b.WriteString("\n")
+	}
+	b.WriteString("Enter run, Esc cancel")
+	return m.styles.cmdline.Render(b.String())
+}
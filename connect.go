@@ -0,0 +1,67 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// "Ready to accept connections for tunnel: quiet-fog-4vk3.usw2",
+	// "Tunnel ID: my-web-tunnel.usw2"
+	hostTunnelRe = regexp.MustCompile(`(?i)(?:for tunnel|tunnel id)\s*:?\s+([a-z0-9][a-z0-9.-]*[a-z0-9])`)
+	// first column of a `devtunnel list` row, e.g. "my-web-tunnel.usw2"
+	listTunnelRe = regexp.MustCompile(`^([a-z0-9][a-z0-9-]*\.[a-z0-9]+)\b`)
+)
+
+// hostArgTunnel returns the tunnel id passed to `devtunnel host`, if any.
+func hostArgTunnel(args []string) string {
+	if len(args) > 2 && args[0] == "devtunnel" && args[1] == "host" && !strings.HasPrefix(args[2], "-") {
+		return args[2]
+	}
+	return ""
+}
+
+// noteHostedTunnel remembers which tunnel a running `host` serves, from its
+// arguments or, for a temporary tunnel, from its output.
+func (m model) noteHostedTunnel(line string) model {
+	if m.runSubcmd != "host" {
+		return m
+	}
+	if sm := hostTunnelRe.FindStringSubmatch(line); sm != nil {
+		m.hostedTunnel = sm[1]
+	}
+	return m
+}
+
+// connectTarget picks the tunnel to share: the list row under the output
+// cursor, then the hosted tunnel, then the default one.
+func (m model) connectTarget() (id, source string) {
+	if m.focusPane == 2 && strings.HasPrefix(m.lastText, "devtunnel list") {
+		if sm := listTunnelRe.FindStringSubmatch(strings.TrimSpace(m.cursorText())); sm != nil {
+			return sm[1], "selected"
+		}
+	}
+	if m.hostedTunnel != "" {
+		return m.hostedTunnel, "hosted"
+	}
+	if m.defaultTunnel != "" {
+		return m.defaultTunnel, "default"
+	}
+	return "", ""
+}
+
+// copyConnectCommand copies the `devtunnel connect` command for the
+// current tunnel and shows it in the status bar.
+func (m model) copyConnectCommand() model {
+	id, source := m.connectTarget()
+	if id == "" {
+		m.statusErr = true
+		m.statusText = "no tunnel to connect to: host one, set a default or select a row of list output"
+		return m
+	}
+	cmd := shellJoin([]string{"devtunnel", "connect", id})
+	copyToClipboard(cmd)
+	m.statusErr = false
+	m.statusText = "copied " + cmd + " (" + source + " tunnel)"
+	return m
+}
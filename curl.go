@@ -0,0 +1,44 @@
+package main
+
+import "strings"
+
+// curlTarget picks the HTTP endpoint to build a curl command for: the
+// selected link, a link on the cursor line, then the first tunnel endpoint
+// (a devtunnels.ms host) in the output, then any URL.
+func (m model) curlTarget() (string, string) {
+	if m.linkIdx >= 0 && m.linkIdx < len(m.links) && m.links[m.linkIdx].line == m.outCursor {
+		return m.links[m.linkIdx].url, "selected"
+	}
+	if m.focusPane == 2 {
+		for _, l := range m.links {
+			if l.line == m.outCursor {
+				return l.url, "cursor line"
+			}
+		}
+	}
+	for _, l := range m.links {
+		if strings.Contains(l.url, ".devtunnels.ms") {
+			return l.url, "tunnel endpoint"
+		}
+	}
+	if len(m.links) > 0 {
+		return m.links[0].url, "first URL"
+	}
+	return "", ""
+}
+
+// copyCurlCommand copies a curl command that shows the response headers of
+// the endpoint, for a quick connectivity check.
+func (m model) copyCurlCommand() model {
+	u, source := m.curlTarget()
+	if u == "" {
+		m.statusErr = true
+		m.statusText = "no HTTP endpoint in the output (host a tunnel or show one with ports first)"
+		return m
+	}
+	cmd := shellJoin([]string{"curl", "-i", u})
+	copyToClipboard(cmd)
+	m.statusErr = false
+	m.statusText = "copied " + cmd + " (" + source + ")"
+	return m
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type customCommandConfig struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	BaseArgs    []string `json:"baseArgs"`
+	Required    []string `json:"required"`
+	Optional    string   `json:"optional"`
+	Example     string   `json:"example"`
+	// Sensitive names required fields or placeholders holding secrets whose
+	// names do not already look like one (token, password, ...).
+	Sensitive []string `json:"sensitive"`
+}
+
+type customCategoryConfig struct {
+	Name     string                `json:"name"`
+	Commands []customCommandConfig `json:"commands"`
+}
+
+// mergeCustomCategories adds commands from config to the catalog. A
+// category whose name matches a built-in one is extended; otherwise a new
+// category is appended. Invalid entries are skipped with a warning.
+func mergeCustomCategories(cats []commandCategory, custom []customCategoryConfig) ([]commandCategory, []string) {
+	var warnings []string
+	for i, cc := range custom {
+		name := strings.TrimSpace(cc.Name)
+		if name == "" {
+			warnings = append(warnings, fmt.Sprintf("customCategories #%d skipped: name is required", i+1))
+			continue
+		}
+		idx := -1
+		for j, c := range cats {
+			if strings.EqualFold(c.name, name) {
+				idx = j
+			}
+		}
+		if idx < 0 {
+			cats = append(cats, commandCategory{name: name})
+			idx = len(cats) - 1
+		}
+		for j, cmd := range cc.Commands {
+			item, err := customCommand(cmd)
+			if err == nil && findCommand(cats, item.name) != nil {
+				err = fmt.Errorf("a command named %q already exists", item.name)
+			}
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("customCategories.%s command #%d skipped: %v", name, j+1, err))
+				continue
+			}
+			cats[idx].commands = append(cats[idx].commands, item)
+		}
+		if len(cats[idx].commands) == 0 {
+			warnings = append(warnings, fmt.Sprintf("customCategories.%s skipped: no valid commands", name))
+			cats = append(cats[:idx], cats[idx+1:]...)
+		}
+	}
+	return cats, warnings
+}
+
+// duplicateCommandWarnings reports command names, compared without case,
+// that appear more than once across categories. Lookups by name (aliases,
+// favorites, the selection memory) only ever find the first one.
+func duplicateCommandWarnings(cats []commandCategory) []string {
+	var warnings []string
+	seen := map[string]string{} // lowercased name -> first category
+	for _, c := range cats {
+		for _, item := range c.commands {
+			key := strings.ToLower(item.name)
+			if first, ok := seen[key]; ok {
+				warnings = append(warnings, fmt.Sprintf("duplicate command name %q in %s (also in %s); only the first is reachable by name", item.name, c.name, first))
+				continue
+			}
+			seen[key] = c.name
+		}
+	}
+	return warnings
+}
+
+func customCommand(cc customCommandConfig) (commandItem, error) {
+	name := strings.TrimSpace(cc.Name)
+	if name == "" {
+		return commandItem{}, fmt.Errorf("name is required")
+	}
+	var base []string
+	for _, a := range cc.BaseArgs {
+		base = append(base, strings.Fields(a)...)
+	}
+	if len(base) == 0 {
+		return commandItem{}, fmt.Errorf("baseArgs is required")
+	}
+	if base[0] == "devtunnel" {
+		return commandItem{}, fmt.Errorf("baseArgs must not include the devtunnel binary")
+	}
+	params := workflowParams(base)
+	required := append([]string{}, params...)
+	for _, r := range cc.Required {
+		if strings.TrimSpace(r) == "" {
+			return commandItem{}, fmt.Errorf("required field names must not be empty")
+		}
+		if !slices.Contains(params, r) {
+			required = append(required, r)
+		}
+	}
+	for _, s := range cc.Sensitive {
+		if !slices.Contains(required, s) {
+			return commandItem{}, fmt.Errorf("sensitive field %q is not a required field or placeholder", s)
+		}
+	}
+	desc := cc.Description
+	if desc == "" {
+		desc = "devtunnel " + strings.Join(base, " ")
+	}
+	return commandItem{
+		name:        name,
+		description: desc,
+		baseArgs:    base,
+		params:      params,
+		required:    required,
+		optional:    strings.TrimSpace(cc.Optional),
+		example:     cc.Example,
+		sensitive:   cc.Sensitive,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+)
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffAdd
+	diffDel
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// maxDiffCells bounds the LCS table; larger inputs degrade to a plain
+// remove-all/add-all diff of the differing middle section.
+const maxDiffCells = 4_000_000
+
+// diffLines computes a line diff from a to b using an LCS table over the
+// section left after trimming common prefix and suffix.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	out := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		out = append(out, diffLine{diffEqual, l})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(ma)*len(mb) > maxDiffCells {
+		for _, l := range ma {
+			out = append(out, diffLine{diffDel, l})
+		}
+		for _, l := range mb {
+			out = append(out, diffLine{diffAdd, l})
+		}
+	} else {
+		out = append(out, lcsDiff(ma, mb)...)
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		out = append(out, diffLine{diffEqual, l})
+	}
+	return out
+}
+
+func lcsDiff(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []diffLine
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{diffDel, a[i]})
+			i++
+		default:
+			out = append(out, diffLine{diffAdd, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, diffLine{diffDel, a[i]})
+	}
+	for ; j < m; j++ {
+		out = append(out, diffLine{diffAdd, b[j]})
+	}
+	return out
+}
+
+// renderDiff formats a diff with +/- markers, returning the text and the
+// number of added and removed lines.
+func (s styles) renderDiff(lines []diffLine) (string, int, int) {
+	var b strings.Builder
+	added, removed := 0, 0
+	for _, l := range lines {
+		switch l.op {
+		case diffAdd:
+			added++
+			b.WriteString(s.ok.Render("+ " + l.text))
+		case diffDel:
+			removed++
+			b.WriteString(s.err.Render("- " + l.text))
+		default:
+			b.WriteString(s.dim.Render("  " + l.text))
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), added, removed
+}
+
+func splitLines(s string) []string {
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// discoveredCategory holds subcommands found in `devtunnel --help` that the
+// built-in catalog does not know.
+const discoveredCategory = "Discovered"
+
+// helpCommand is one entry of the help output's command list.
+type helpCommand struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// discoveryCache keeps the parsed help output for one devtunnel binary, so
+// it is only run again when the binary changes.
+type discoveryCache struct {
+	Binary   string        `json:"binary"`
+	Size     int64         `json:"size"`
+	ModTime  int64         `json:"modTime"`
+	Commands []helpCommand `json:"commands"`
+}
+
+type discoveredMsg struct {
+	commands []helpCommand
+	err      error
+}
+
+// helpColumnsRe splits a help row into its name and description columns,
+// which are separated by at least two spaces.
+var helpColumnsRe = regexp.MustCompile(`^\s+(\S.*?)(?:\s{2,}(\S.*))?$`)
+
+// parseHelpCommands reads the "Commands:" section of `devtunnel --help`.
+// Names may list aliases ("list, ls") and argument placeholders
+// ("show <tunnel-id>"); only the first word is kept. Deeper-indented
+// continuation lines are skipped.
+func parseHelpCommands(help string) ([]helpCommand, error) {
+	var out []helpCommand
+	in := false
+	indent := -1
+	for _, line := range strings.Split(strings.ReplaceAll(help, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !in {
+			in = strings.EqualFold(trimmed, "commands:")
+			continue
+		}
+		if trimmed == "" {
+			if len(out) > 0 {
+				break
+			}
+			continue
+		}
+		lead := len(line) - len(strings.TrimLeft(line, " \t"))
+		if lead == 0 {
+			break
+		}
+		if indent < 0 {
+			indent = lead
+		}
+		if lead > indent {
+			continue
+		}
+		m := helpColumnsRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		first, _, _ := strings.Cut(m[1], ",")
+		words := strings.Fields(first)
+		if len(words) == 0 {
+			continue
+		}
+		name := words[0]
+		if strings.HasPrefix(name, "-") || strings.HasPrefix(name, "<") {
+			continue
+		}
+		out = append(out, helpCommand{Name: name, Description: strings.TrimSpace(m[2])})
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no command list found in help output")
+	}
+	return out, nil
+}
+
+func discoveryCachePath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "discovered.json"), nil
+}
+
+// discoverCommandsCmd parses `devtunnel --help`, reusing the cached result
+// while the binary is unchanged.
+func (m model) discoverCommandsCmd() tea.Cmd {
+	procs := m.procs
+	return func() tea.Msg {
+		bin, err := exec.LookPath(procs.program("devtunnel"))
+		if err != nil {
+			return discoveredMsg{err: err}
+		}
+		info, err := os.Stat(bin)
+		if err != nil {
+			return discoveredMsg{err: err}
+		}
+		key := discoveryCache{Binary: bin, Size: info.Size(), ModTime: info.ModTime().Unix()}
+		cachePath, cacheErr := discoveryCachePath()
+		if cacheErr == nil {
+			if data, err := os.ReadFile(cachePath); err == nil {
+				var cached discoveryCache
+				if json.Unmarshal(data, &cached) == nil && cached.Binary == key.Binary && cached.Size == key.Size && cached.ModTime == key.ModTime && len(cached.Commands) > 0 {
+					return discoveredMsg{commands: cached.Commands}
+				}
+			}
+		}
+		out, err := runQuiet(procs, "devtunnel", "--help")
+		if err != nil && out == "" {
+			return discoveredMsg{err: err}
+		}
+		cmds, err := parseHelpCommands(out)
+		if err != nil {
+			return discoveredMsg{err: err}
+		}
+		if cacheErr == nil {
+			key.Commands = cmds
+			if data, err := json.MarshalIndent(key, "", "  "); err == nil {
+				if os.MkdirAll(filepath.Dir(cachePath), 0o755) == nil {
+					_ = os.WriteFile(cachePath, data, 0o644)
+				}
+			}
+		}
+		return discoveredMsg{commands: cmds}
+	}
+}
+
+// onDiscovered adds the subcommands the catalog lacks as a Discovered
+// category. On failure the static catalog stays as it is.
+func (m model) onDiscovered(msg discoveredMsg) model {
+	if msg.err != nil {
+		m.statusErr = true
+		m.statusText = "command discovery failed, using the built-in catalog: " + msg.err.Error()
+		return m
+	}
+	for _, h := range m.cfg.Categories.Hidden {
+		if strings.EqualFold(strings.TrimSpace(h), discoveredCategory) {
+			return m
+		}
+	}
+	known := map[string]bool{}
+	for _, cat := range catalog() {
+		for _, c := range cat.commands {
+			if len(c.baseArgs) > 0 {
+				known[c.baseArgs[0]] = true
+			}
+		}
+	}
+	var cat commandCategory
+	cat.name = discoveredCategory
+	for _, h := range msg.commands {
+		if known[h.Name] || h.Name == "help" || findCommand(m.categories, h.Name) != nil {
+			continue
+		}
+		desc := h.Description
+		if desc == "" {
+			desc = "devtunnel " + h.Name
+		}
+		cat.commands = append(cat.commands, commandItem{
+			name:        h.Name,
+			description: desc,
+			baseArgs:    []string{h.Name},
+			optional:    "args and flags",
+			example:     h.Name + " --help",
+		})
+	}
+	if len(cat.commands) == 0 {
+		return m
+	}
+	m.categories = append(m.categories, cat)
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("discovered %d new devtunnel command(s), see the %s category", len(cat.commands), discoveredCategory)
+	return m
+}
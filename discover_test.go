@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHelpCommands(t *testing.T) {
+	help := "Usage:\r\n  devtunnel [command] [options]\r\n\r\n" +
+		"Commands:\n" +
+		"  list, ls              List tunnels\n" +
+		"  show <tunnel-id>      Show tunnel details\n" +
+		"      continued description line\n" +
+		"  , odd                 Row starting with a comma\n" +
+		"  ,                     Only a comma\n" +
+		"  --flag                Option in the wrong section\n" +
+		"  <arg>                 Placeholder row\n" +
+		"  echo\n" +
+		"\n" +
+		"  later                 After the section\n"
+	got, err := parseHelpCommands(help)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []helpCommand{
+		{Name: "list", Description: "List tunnels"},
+		{Name: "show", Description: "Show tunnel details"},
+		{Name: "echo"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseHelpCommands() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseHelpCommandsWithoutList(t *testing.T) {
+	for _, help := range []string{"", "Usage: devtunnel\n", "Commands:\n  ,\n"} {
+		if got, err := parseHelpCommands(help); err == nil {
+			t.Errorf("parseHelpCommands(%q) = %+v, want an error", help, got)
+		}
+	}
+}
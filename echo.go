@@ -0,0 +1,49 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// longRunning lists subcommands that serve until interrupted rather than
+// exiting on their own.
+var longRunning = map[string]bool{
+	"host": true,
+	"echo": true,
+}
+
+// listenAddrRe matches the line an echo server prints once it is up, e.g.
+// "Listening on http://localhost:8080" or "Echo server listening on port 8080".
+var listenAddrRe = regexp.MustCompile(`(?i)listening (?:on|at)\s+(?:port\s+)?(\S+)`)
+
+// parseListenAddr extracts the listening address from a line of output.
+func parseListenAddr(line string) (string, bool) {
+	sm := listenAddrRe.FindStringSubmatch(line)
+	if sm == nil {
+		return "", false
+	}
+	return strings.TrimRight(sm[1], ".,;"), true
+}
+
+// runningStatus is the status bar text for a command that just started;
+// long-running ones say how to stop them.
+func runningStatus(cmdText string, args []string) string {
+	if longRunning[subcommandName(args)] {
+		return "running " + cmdText + " (Ctrl-C to stop)"
+	}
+	return "running " + cmdText
+}
+
+// noteListenAddr shows where a running echo server listens once its output
+// says so.
+func (m model) noteListenAddr(line string) model {
+	if m.runSubcmd != "echo" || m.listenAddr != "" {
+		return m
+	}
+	if addr, ok := parseListenAddr(line); ok {
+		m.listenAddr = addr
+		m.statusErr = false
+		m.statusText = "echo server listening on " + addr + " (Ctrl-C to stop)"
+	}
+	return m
+}
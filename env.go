@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// envPrefix namespaces the environment variables that override config.
+const envPrefix = "DEVTUNNEL_TUI_"
+
+// envSetting binds one environment variable to a config field.
+type envSetting struct {
+	name  string // without envPrefix
+	apply func(cfg *config, value string) error
+}
+
+func envString(field func(*config) *string) func(*config, string) error {
+	return func(cfg *config, v string) error {
+		*field(cfg) = v
+		return nil
+	}
+}
+
+func envInt(field func(*config) *int) func(*config, string) error {
+	return func(cfg *config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("expected a whole number, got %q", v)
+		}
+		*field(cfg) = n
+		return nil
+	}
+}
+
+func envBool(field func(*config) *bool) func(*config, string) error {
+	return func(cfg *config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", v)
+		}
+		*field(cfg) = b
+		return nil
+	}
+}
+
+var envSettings = []envSetting{
+	{"NOTIFY", envBool(func(c *config) *bool { return &c.Notify.Enabled })},
+	{"NOTIFY_MIN_SECONDS", envInt(func(c *config) *int { return &c.Notify.MinSeconds })},
+	{"NOTIFY_DESKTOP", envBool(func(c *config) *bool { return &c.Notify.Desktop })},
+	{"CACHE_TTL_SECONDS", envInt(func(c *config) *int { return &c.CacheTTLSeconds })},
+	{"ALLOW_SHELL", envBool(func(c *config) *bool { return &c.AllowShell })},
+	{"STARTUP_COMMAND", envString(func(c *config) *string { return &c.StartupCommand })},
+	{"POLL_SECONDS", envInt(func(c *config) *int { return &c.PollSeconds })},
+	{"SET_TITLE", envBool(func(c *config) *bool { return &c.SetTitle })},
+	{"WORK_DIR", envString(func(c *config) *string { return &c.WorkDir })},
+	{"CONFIRM_ARGS", envInt(func(c *config) *int { return &c.ConfirmArgs })},
+	{"COMMAND_LOG", envString(func(c *config) *string { return &c.CommandLog })},
+	{"LOG_MAX_KB", envInt(func(c *config) *int { return &c.LogRetention.MaxKB })},
+	{"LOG_MAX_DAYS", envInt(func(c *config) *int { return &c.LogRetention.MaxDays })},
+	{"HISTORY_SIZE", envInt(func(c *config) *int { return &c.HistorySize })},
+	{"PREFETCH", envBool(func(c *config) *bool { return &c.Prefetch })},
+	{"SAFE_MODE", envBool(func(c *config) *bool { return &c.SafeMode })},
+	{"AUTO_FORM", envBool(func(c *config) *bool { return &c.AutoForm })},
+	{"CONFIRM_NO_ARGS", envBool(func(c *config) *bool { return &c.ConfirmNoArgs })},
+	{"DISCOVER_COMMANDS", envBool(func(c *config) *bool { return &c.DiscoverCommands })},
+	{"SPINNER_STYLE", envString(func(c *config) *string { return &c.Spinner.Style })},
+	{"SPINNER_INTERVAL_MS", envInt(func(c *config) *int { return &c.Spinner.IntervalMS })},
+	{"REDUCED_MOTION", envBool(func(c *config) *bool { return &c.ReducedMotion })},
+	{"DEVTUNNEL_PATH", envString(func(c *config) *string { return &c.DevtunnelPath })},
+	{"TIMEOUT_MINUTES", envInt(func(c *config) *int { return &c.TimeoutMinutes })},
+}
+
+// applyEnv overrides config values from DEVTUNNEL_TUI_* variables, so the
+// order is environment, then config file, then built-in defaults. Invalid
+// values are skipped and reported.
+func applyEnv(cfg *config, lookup func(string) (string, bool)) []string {
+	var warnings []string
+	for _, s := range envSettings {
+		v, ok := lookup(envPrefix + s.name)
+		if !ok {
+			continue
+		}
+		if err := s.apply(cfg, v); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s%s: %v (ignored)", envPrefix, s.name, err))
+		}
+	}
+	return warnings
+}
+
+// loadSettings resolves the effective config from file and environment.
+func loadSettings() (config, []string) {
+	cfg, err := loadConfig()
+	var warnings []string
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	if noAnimation(os.LookupEnv) {
+		cfg.ReducedMotion = true
+	}
+	return cfg, append(warnings, applyEnv(&cfg, os.LookupEnv)...)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFavorites matches the alt+1..alt+9 quick-run keys.
+const maxFavorites = 9
+
+func (m model) favoriteIndex(name string) int {
+	for i, f := range m.state.Favorites {
+		if f == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// templatePrefix marks a favorite that is a saved command line rather than
+// the name of a catalog command.
+const templatePrefix = "devtunnel "
+
+// templateName turns a command-mode line into a favorite entry.
+func templateName(raw string) string {
+	return templatePrefix + strings.TrimSpace(strings.TrimPrefix(raw, templatePrefix))
+}
+
+// templateCommand builds a command from a saved command line. Its
+// {placeholders} become form fields filled in at run time.
+func templateCommand(name string) commandItem {
+	base := splitArgs(strings.TrimPrefix(name, templatePrefix))
+	params := workflowParams(base)
+	return commandItem{
+		name:        name,
+		description: "saved command",
+		baseArgs:    base,
+		params:      params,
+		required:    params,
+	}
+}
+
+// toggleFavorite adds or removes the selected command from favorites and
+// saves the list to state.json.
+func (m model) toggleFavorite() model {
+	cmds := m.visibleCommands()
+	if len(cmds) == 0 {
+		return m
+	}
+	name := cmds[min(m.cmdIdx, len(cmds)-1)].name
+	if i := m.favoriteIndex(name); i >= 0 {
+		m.state.Favorites = append(m.state.Favorites[:i:i], m.state.Favorites[i+1:]...)
+		m.statusErr = false
+		m.statusText = "removed favorite " + name
+		return m.saveFavorites()
+	}
+	return m.addFavorite(name)
+}
+
+// addFavorite appends name, a command name or saved command line, to the
+// favorites.
+func (m model) addFavorite(name string) model {
+	if i := m.favoriteIndex(name); i >= 0 {
+		m.statusErr = false
+		m.statusText = fmt.Sprintf("already favorite %d (alt+%d runs it)", i+1, i+1)
+		return m
+	}
+	if len(m.state.Favorites) >= maxFavorites {
+		m.statusErr = true
+		m.statusText = fmt.Sprintf("at most %d favorites; remove one first", maxFavorites)
+		return m
+	}
+	m.state.Favorites = append(m.state.Favorites, name)
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("favorite %d: %s (alt+%d runs it)", len(m.state.Favorites), name, len(m.state.Favorites))
+	return m.saveFavorites()
+}
+
+func (m model) saveFavorites() model {
+	if err := saveState(m.state); err != nil {
+		m.statusErr = true
+		m.statusText = "could not save favorites: " + err.Error()
+	}
+	return m
+}
+
+// favoriteKey returns the favorite slot (0-based) for alt+1..alt+9.
+func favoriteKey(k tea.KeyMsg) (int, bool) {
+	if !k.Alt || k.Type != tea.KeyRunes || len(k.Runes) != 1 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(k.Runes[0]))
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+// runFavorite runs favorite slot i from anywhere, opening its form when it
+// needs input.
+func (m model) runFavorite(i int) (tea.Model, tea.Cmd) {
+	if i >= len(m.state.Favorites) {
+		m.statusErr = true
+		m.statusText = fmt.Sprintf("no favorite %d (* marks the selected command)", i+1)
+		return m, nil
+	}
+	name := m.state.Favorites[i]
+	if strings.HasPrefix(name, templatePrefix) {
+		return m.runItem(templateCommand(name))
+	}
+	cmd := findCommand(m.categories, name)
+	if cmd == nil {
+		m.statusErr = true
+		m.statusText = "favorite " + name + " is no longer available"
+		return m, nil
+	}
+	return m.runItem(*cmd)
+}
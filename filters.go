@@ -0,0 +1,117 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// commandFilter keeps the commands it returns true for. visibleCommands
+// applies every active filter in turn, so each one can be toggled on its own.
+type commandFilter func(commandItem) bool
+
+// commandTags are the tags a command can carry, in the order Ctrl+T cycles
+// through them.
+var commandTags = []string{"read-only", "cluster", "destructive", "advanced", "workflow"}
+
+// tags derives a command's tags from its catalog properties.
+func (c commandItem) tags() []string {
+	var out []string
+	if c.cacheable {
+		out = append(out, "read-only")
+	}
+	if c.clusterFlag {
+		out = append(out, "cluster")
+	}
+	if c.destructive {
+		out = append(out, "destructive")
+	}
+	if c.advanced {
+		out = append(out, "advanced")
+	}
+	if c.workflow != nil {
+		out = append(out, "workflow")
+	}
+	return out
+}
+
+// commandFilters returns the active filters: the advanced toggle, the text
+// (or regex) filter and the tag filter.
+func (m model) commandFilters() []commandFilter {
+	var out []commandFilter
+	if !m.showAdvanced {
+		out = append(out, func(c commandItem) bool { return !c.advanced })
+	}
+	if flt := strings.TrimSpace(strings.ToLower(m.filterInput.Value())); flt != "" {
+		// an invalid regex shows everything rather than nothing
+		if !m.filterRegex {
+			out = append(out, func(c commandItem) bool { return strings.Contains(c.haystack(), flt) })
+		} else if re := m.filterRe; re != nil {
+			out = append(out, func(c commandItem) bool { return re.MatchString(c.haystack()) })
+		}
+	}
+	if tag := m.filterTag; tag != "" {
+		out = append(out, func(c commandItem) bool { return slices.Contains(c.tags(), tag) })
+	}
+	return out
+}
+
+// haystack is the lowercased text the filter matches against.
+func (c commandItem) haystack() string {
+	return strings.ToLower(c.name + " " + c.description + " " + strings.Join(c.baseArgs, " ") + " " + strings.Join(c.aliases, " "))
+}
+
+func applyFilters(items []commandItem, filters []commandFilter) []commandItem {
+	out := make([]commandItem, 0, len(items))
+	for _, item := range items {
+		keep := true
+		for _, f := range filters {
+			if !f(item) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// cycleFilterTag steps the tag filter through the tags used in the
+// catalog, then back to none.
+func (m model) cycleFilterTag() model {
+	used := map[string]bool{}
+	for _, cat := range m.categories {
+		for _, c := range cat.commands {
+			for _, t := range c.tags() {
+				used[t] = true
+			}
+		}
+	}
+	var tags []string
+	for _, t := range commandTags {
+		if used[t] {
+			tags = append(tags, t)
+		}
+	}
+	i := slices.Index(tags, m.filterTag)
+	m.filterTag = ""
+	if i+1 < len(tags) {
+		m.filterTag = tags[i+1]
+	}
+	m.cmdIdx = 0
+	return m
+}
+
+// filterSummary lists the filters other than the text one, for the
+// commands pane.
+func (m model) filterSummary() string {
+	var parts []string
+	if m.filterAll {
+		parts = append(parts, "all categories")
+	}
+	if m.filterTag != "" {
+		parts = append(parts, "tag:"+m.filterTag)
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hookTimeout bounds a post-run hook so a stuck script cannot pile up.
+const hookTimeout = time.Minute
+
+// hookDoneMsg reports a finished post-run hook.
+type hookDoneMsg struct {
+	name   string // the hooks key that matched, e.g. "create"
+	hook   string
+	start  time.Time
+	output string
+	err    error
+}
+
+// hookFor returns the configured hook for a devtunnel command, matching the
+// longest run of leading subcommand words ("port create" before "port").
+func (m model) hookFor(args []string) (name, hook string) {
+	if len(m.cfg.Hooks) == 0 || len(args) < 2 || args[0] != "devtunnel" {
+		return "", ""
+	}
+	for n := len(args) - 1; n > 0; n-- {
+		name = strings.Join(args[1:1+n], " ")
+		if hook, ok := m.cfg.Hooks[name]; ok && strings.TrimSpace(hook) != "" {
+			return name, hook
+		}
+	}
+	return "", ""
+}
+
+// runHook starts the hook for a successful run, if one is configured, and
+// notes it in the status bar. Safe mode skips hooks like other shell
+// commands.
+func (m model) runHook(msg runFinishedMsg) (model, tea.Cmd) {
+	name, hook := m.hookFor(msg.args)
+	if name == "" || msg.err != nil {
+		return m, nil
+	}
+	if m.safeMode {
+		m.statusText += "; hook for " + name + " skipped in safe mode"
+		return m, nil
+	}
+	m.statusText += "; running hook for " + name
+	m = m.logCommand("hook", name+": "+hook)
+	return m, m.runHookCmd(name, hook, msg)
+}
+
+// runHookCmd runs hook with sh -c in the working directory. The command's
+// output is on stdin and the command itself in DEVTUNNEL_TUI_COMMAND. It is
+// tracked with the other child processes so quitting stops it.
+func (m model) runHookCmd(name, hook string, msg runFinishedMsg) tea.Cmd {
+	procs := m.procs
+	dir := m.workDir
+	return func() tea.Msg {
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		defer cancel()
+		ctx, release := procs.track(ctx)
+		defer release()
+		c := exec.CommandContext(ctx, "sh", "-c", hook)
+		c.WaitDelay = 2 * time.Second
+		c.Dir = dir
+		c.Env = append(os.Environ(), "DEVTUNNEL_TUI_COMMAND="+shellJoin(msg.args))
+		c.Stdin = strings.NewReader(msg.output)
+		out, err := c.CombinedOutput()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", hookTimeout)
+		}
+		return hookDoneMsg{name: name, hook: hook, start: start, output: string(out), err: err}
+	}
+}
+
+// onHookDone adds the hook's output to the session log, where L shows it,
+// and reports the result.
+func (m model) onHookDone(msg hookDoneMsg) model {
+	entry := logEntry{cmdText: "hook " + msg.name + ": " + msg.hook, args: []string{"hook", msg.name}, start: msg.start, end: time.Now(), output: msg.output, err: msg.err}
+	m = m.appendSessionLog(entry)
+	m = m.logCommand("hook", fmt.Sprintf("%s [%s, %s]", msg.name, entry.exitStatus(), entry.end.Sub(entry.start).Round(time.Millisecond)))
+	m.statusErr = msg.err != nil
+	m.statusText = fmt.Sprintf("hook for %s: %s (L shows its output)", msg.name, entry.exitStatus())
+	return m
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// isoTimeRe matches RFC 3339 style timestamps as printed by devtunnel,
+// with or without fractional seconds and zone.
+var isoTimeRe = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?\b`)
+
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func parseISOTime(s string) (time.Time, bool) {
+	for _, layout := range isoLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// relativeTime describes t relative to now, e.g. "2 days ago" or
+// "in 3 hours", in the largest whole unit.
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, u := range units {
+		if n := int(d / u.size); n >= 1 {
+			text := fmt.Sprintf("%d %s", n, u.name)
+			if n > 1 {
+				text += "s"
+			}
+			if future {
+				return "in " + text
+			}
+			return text + " ago"
+		}
+	}
+	return "just now"
+}
+
+// humanizeTimes replaces the timestamps in output with relative times.
+func humanizeTimes(output string, now time.Time) string {
+	return isoTimeRe.ReplaceAllStringFunc(output, func(s string) string {
+		if t, ok := parseISOTime(s); ok {
+			return relativeTime(t, now)
+		}
+		return s
+	})
+}
+
+// toggleHumanTimes switches timestamps in results between raw and
+// relative, redrawing the latest result in place.
+func (m model) toggleHumanTimes() model {
+	m.humanTimes = !m.humanTimes
+	m.statusErr = false
+	m.statusText = "raw timestamps"
+	if m.humanTimes {
+		m.statusText = "relative timestamps"
+	}
+	if m.lastText == "" || m.running {
+		return m
+	}
+	body := m.resultBody()
+	if body != "" {
+		body = m.formatOutput(m.lastText, body)
+	}
+	offset := m.viewport.YOffset
+	m = m.setOutput("$ " + m.lastText + "\n\n" + body)
+	m.viewport.SetYOffset(offset)
+	return m
+}
@@ -0,0 +1,37 @@
+package main
+
+import "github.com/charmbracelet/x/ansi"
+
+// Key hints shown after the one-line inputs in the bottom bar. Input widths
+// leave room for them.
+const (
+	cmdHint    = "  (Enter run, Ctrl+S save as favorite, Alt+J/A/P/L/D/E/C/V flags, Esc cancel)"
+	shellHint  = "  (shell via sh -c, Enter run, Esc cancel)"
+	filterHint = "  (Enter apply, Esc cancel, Ctrl+R regex, Ctrl+A all categories, Ctrl+T tag)"
+	flagsHint  = "  (Enter run, Esc cancel)"
+	promptHint = "  (Enter apply, Esc cancel)"
+)
+
+// inputWidth is the room left for typed text in a bottom bar line holding
+// prompt and hint: the terminal width less the bar's padding and the
+// cursor cell.
+func (m model) inputWidth(prompt, hint string) int {
+	return max(10, m.width-ansi.StringWidth(prompt)-ansi.StringWidth(hint)-3)
+}
+
+// resizeInputs fits every text input to the terminal width. It runs on
+// each resize and when an input is opened with a new prompt.
+func (m model) resizeInputs() model {
+	if m.width == 0 {
+		return m
+	}
+	m.filterInput.Width = m.inputWidth(m.filterInput.Prompt, filterHint)
+	m.cmdInput.Width = m.inputWidth(m.cmdInput.Prompt, cmdHint) // the longer of the two hints
+	m.flagsInput.Width = m.inputWidth(m.flagsInput.Prompt, flagsHint)
+	m.promptInput.Width = m.inputWidth(m.promptInput.Prompt, promptHint)
+	m.wizardInput.Width = m.inputWidth(m.wizardInput.Prompt, "")
+	for i := range m.formInputs {
+		m.formInputs[i].Width = m.inputWidth(m.formInputs[i].Prompt, "")
+	}
+	return m
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptHandling says how a command that would ask devtunnel's own
+// questions is run, since captured commands get no stdin and would stop at
+// the prompt.
+type promptHandling struct {
+	// forceFlag skips devtunnel's prompt; it is only added after the
+	// command has been confirmed in the TUI.
+	forceFlag string
+	// interactive hands the terminal to the command so it can prompt.
+	interactive bool
+}
+
+// promptHandlingFor returns the handling of the catalog entry whose
+// subcommand parts runs, preferring the longest match ("user login" over
+// "user").
+func (m model) promptHandlingFor(parts []string) promptHandling {
+	var best commandItem
+	if len(parts) == 0 || parts[0] != "devtunnel" {
+		return promptHandling{}
+	}
+	for _, cat := range m.categories {
+		for _, c := range cat.commands {
+			n := len(c.baseArgs)
+			if n == 0 || n <= len(best.baseArgs) || len(parts)-1 < n || !slices.Equal(parts[1:1+n], c.baseArgs) {
+				continue
+			}
+			best = c
+		}
+	}
+	return best.prompts
+}
+
+// withForceFlag asks for confirmation of a command whose devtunnel prompt is
+// skipped with a flag, adding the flag to what runs once confirmed.
+func (m model) withForceFlag(args []sourcedArg) (model, bool) {
+	h := m.promptHandlingFor(argValues(args))
+	if h.forceFlag == "" || slices.Contains(argValues(args), h.forceFlag) {
+		return m, false
+	}
+	m.confirmMode = true
+	m.confirmArgs = append(args, sourcedArg{value: h.forceFlag, source: "answers devtunnel's prompt (confirmed here)"})
+	return m, true
+}
+
+// forced adds the flag that skips the devtunnel prompt of parts, for a run
+// that has already been confirmed in the TUI.
+func (m model) forced(parts []string) []string {
+	h := m.promptHandlingFor(parts)
+	if h.forceFlag == "" || slices.Contains(parts, h.forceFlag) {
+		return parts
+	}
+	return append(slices.Clone(parts), h.forceFlag)
+}
+
+// withoutForceFlag drops the flag that skips the devtunnel prompt of parts,
+// so a command is kept for reruns as it was before confirmation added it.
+func (m model) withoutForceFlag(parts []string) []string {
+	h := m.promptHandlingFor(parts)
+	if h.forceFlag == "" {
+		return parts
+	}
+	return slices.DeleteFunc(slices.Clone(parts), func(p string) bool { return p == h.forceFlag })
+}
+
+// confirmForced is withForceFlag for an argv that was not assembled from a
+// form, such as a typed command or a rerun. A flag already in parts is
+// confirmed again rather than trusted.
+func (m model) confirmForced(parts []string, source string) (model, bool) {
+	parts = m.withoutForceFlag(parts)
+	args := make([]sourcedArg, len(parts))
+	for i, p := range parts {
+		args[i] = sourcedArg{value: p, source: source}
+	}
+	return m.withForceFlag(args)
+}
+
+// rerun runs parts again, asking first when it only runs with a force flag.
+func (m model) rerun(parts []string) (tea.Model, tea.Cmd) {
+	if next, asked := m.confirmForced(parts, "previous command"); asked {
+		return next, nil
+	}
+	return m, m.runCommandCmd(parts)
+}
+
+// runInteractiveCmd runs parts with the terminal handed over, reporting the
+// result like a captured run without output.
+func (m model) runInteractiveCmd(parts []string, cmdText string) tea.Cmd {
+	c := exec.Command(m.procs.program(parts[0]), parts[1:]...)
+	c.Dir = m.workDir
+	return tea.Sequence(
+		func() tea.Msg {
+			return runStartedMsg{cmdText: cmdText, args: parts, interrupt: context.CancelFunc(func() {})}
+		},
+		tea.ExecProcess(c, func(err error) tea.Msg {
+			return runFinishedMsg{cmdText: cmdText, args: parts, output: "(ran interactively in the terminal)", err: err}
+		}),
+	)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startedArgs runs the first step of a command sequence and returns the argv
+// it reports starting.
+func startedArgs(t *testing.T, cmd tea.Cmd) []string {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("no command was started")
+	}
+	seq := reflect.ValueOf(cmd())
+	if seq.Kind() != reflect.Slice || seq.Len() == 0 {
+		t.Fatalf("expected a command sequence, got %T", seq.Interface())
+	}
+	started, ok := seq.Index(0).Interface().(tea.Cmd)().(runStartedMsg)
+	if !ok {
+		t.Fatal("first step did not report a started run")
+	}
+	return started.args
+}
+
+func catalogCommand(t *testing.T, name string) commandItem {
+	t.Helper()
+	for _, cat := range catalog() {
+		for _, c := range cat.commands {
+			if c.name == name {
+				return c
+			}
+		}
+	}
+	t.Fatalf("no catalog command %q", name)
+	return commandItem{}
+}
+
+func TestForceFlagAfterConfirmation(t *testing.T) {
+	deleteAll := []string{"devtunnel", "delete-all"}
+	forced := []string{"devtunnel", "delete-all", "--force"}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	base := model{categories: catalog(), secrets: secretSet{}, devtunnelFound: true}
+
+	tests := []struct {
+		name  string
+		start func(m model) (tea.Model, tea.Cmd)
+	}{
+		{"selected without confirmNoArgs", func(m model) (tea.Model, tea.Cmd) {
+			return m.runItem(catalogCommand(t, "delete-all"))
+		}},
+		{"selected with confirmNoArgs", func(m model) (tea.Model, tea.Cmd) {
+			m.cfg.ConfirmNoArgs = true
+			return m.runItem(catalogCommand(t, "delete-all"))
+		}},
+		{"rerun of the last command", func(m model) (tea.Model, tea.Cmd) {
+			m.lastCmd = deleteAll
+			return m.rerun(m.lastCmd)
+		}},
+		{"rerun of a forced command", func(m model) (tea.Model, tea.Cmd) {
+			m.failedCmd = forced
+			return m.retryFailed()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, cmd := tt.start(base)
+			m := next.(model)
+			if cmd != nil || !m.confirmMode {
+				t.Fatal("ran without asking for confirmation")
+			}
+			if got := argValues(m.confirmArgs); !reflect.DeepEqual(got, forced) {
+				t.Errorf("confirmation shows %q, want %q", got, forced)
+			}
+			next, cmd = m.updateConfirm(enter)
+			m = next.(model)
+			if m.confirmMode {
+				t.Fatal("a second confirmation was asked")
+			}
+			if got := startedArgs(t, cmd); !reflect.DeepEqual(got, forced) {
+				t.Errorf("ran %q, want %q", got, forced)
+			}
+			if !reflect.DeepEqual(m.lastCmd, deleteAll) {
+				t.Errorf("lastCmd = %q, want %q", m.lastCmd, deleteAll)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFinishedJobs bounds how many finished background jobs stay listed.
+const maxFinishedJobs = 10
+
+// job is a command running (or finished) in the background. Its messages
+// are told apart from the foreground run by the stream channel.
+type job struct {
+	id        int
+	cmdText   string
+	args      []string
+	ch        <-chan tea.Msg
+	interrupt context.CancelFunc
+	start     time.Time
+	end       time.Time
+	lines     []string
+	done      bool
+	err       error
+}
+
+func (j *job) state(procs *procTable) string {
+	switch {
+	case !j.done:
+		return "running " + time.Since(j.start).Round(time.Second).String()
+	case j.err != nil:
+		status, _ := describeFailure(procs, j.args, j.err)
+		return status
+	}
+	return "done"
+}
+
+// jobFor returns the background job streaming on ch, if any.
+func (m model) jobFor(ch <-chan tea.Msg) *job {
+	if ch == nil {
+		return nil
+	}
+	for _, j := range m.jobs {
+		if j.ch == ch {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m model) runningJobs() int {
+	n := 0
+	for _, j := range m.jobs {
+		if !j.done {
+			n++
+		}
+	}
+	return n
+}
+
+// addJob lists a started run as a background job.
+func (m model) addJob(cmdText string, args []string, ch <-chan tea.Msg, interrupt context.CancelFunc, start time.Time) (model, *job) {
+	m.nextJobID++
+	j := &job{id: m.nextJobID, cmdText: cmdText, args: args, ch: ch, interrupt: interrupt, start: start}
+	finished := 0
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		if m.jobs[i].done {
+			finished++
+			if finished >= maxFinishedJobs {
+				m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+			}
+		}
+	}
+	m.jobs = append(m.jobs, j)
+	return m, j
+}
+
+// runInBackground runs the selected command as a background job, after its
+// form if it has one. Nothing is left pending when no command starts, e.g.
+// on a cache hit.
+func (m model) runInBackground() (tea.Model, tea.Cmd) {
+	m.nextBackground = true
+	next, cmd := m.runSelected()
+	nm, ok := next.(model)
+	if ok && cmd == nil && !nm.formMode && !nm.confirmMode && nm.wizard == nil {
+		nm.nextBackground = false
+		return nm, nil
+	}
+	return next, cmd
+}
+
+// detachForeground moves the running foreground command into the
+// background, freeing the UI for the next command.
+func (m model) detachForeground() model {
+	if !m.running || m.runCh == nil || m.workflow != nil || m.watching {
+		m.statusErr = true
+		m.statusText = "nothing to send to the background (workflows and watches stay in front)"
+		return m
+	}
+	var j *job
+	m, j = m.addJob(m.runText, m.runArgs, m.runCh, m.interrupt, m.runStart)
+	for _, l := range m.liveLines {
+		j.lines = append(j.lines, l.text)
+	}
+	m.running = false
+	m.interrupt = nil
+	m.runCh = nil
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("job %d running in background: %s (J to list)", j.id, j.cmdText)
+	return m
+}
+
+// onJobOutput records a line from a background job, redrawing it on the
+// next tick when it is being viewed.
+func (m model) onJobOutput(j *job, line string) (model, tea.Cmd) {
+	j.lines = append(j.lines, line)
+	if m.viewingJob != j.id {
+		return m, nil
+	}
+	return m.scheduleRedraw()
+}
+
+// onJobFinished records a background job's result in the session log and
+// command log, and reports it in the status bar.
+func (m model) onJobFinished(j *job, msg runFinishedMsg) (model, tea.Cmd) {
+	j.done = true
+	j.err = msg.err
+	j.end = time.Now()
+	j.lines = strings.Split(strings.TrimRight(msg.output, "\n"), "\n")
+	entry := logEntry{cmdText: j.cmdText, args: j.args, start: j.start, end: j.end, output: msg.output, err: msg.err}
+	m = m.appendSessionLog(entry)
+	m = m.logCommand("finish", fmt.Sprintf("%s [%s, %s]", j.cmdText, entry.exitStatus(), j.end.Sub(j.start).Round(time.Millisecond)))
+	m.recordCache(msg)
+	if m.viewingJob == j.id {
+		m = m.showJob(j)
+	}
+	m.statusErr = msg.err != nil
+	m.statusText = fmt.Sprintf("job %d %s: %s", j.id, j.state(m.procs), j.cmdText)
+	m, hook := m.runHook(msg)
+	return m, tea.Batch(hook, notifyCmd(m.cfg.Notify, "job "+j.cmdText, msg.err != nil, j.end.Sub(j.start)))
+}
+
+func (m model) showJob(j *job) model {
+	m = m.setOutput(fmt.Sprintf("$ %s  [job %d, %s]\n\n%s", j.cmdText, j.id, j.state(m.procs), strings.Join(j.lines, "\n")))
+	m.viewport.GotoBottom()
+	return m
+}
+
+// openJobsPicker lists the foreground result and every background job.
+func (m model) openJobsPicker() model {
+	if len(m.jobs) == 0 {
+		m.statusErr = true
+		m.statusText = "no background jobs (& runs the selection in the background)"
+		return m
+	}
+	items := []string{"foreground: " + valueOr(m.lastText, "(none)")}
+	idx := 0
+	for i, j := range m.jobs {
+		items = append(items, fmt.Sprintf("job %d  %-14s %s", j.id, j.state(m.procs), j.cmdText))
+		if j.id == m.viewingJob {
+			idx = i + 1
+		}
+	}
+	return m.openPicker(pickJob, "Show output of", items, idx)
+}
+
+// viewJob switches the output pane to job i of the picker, where 0 is the
+// foreground result.
+func (m model) viewJob(i int) model {
+	if i == 0 || i > len(m.jobs) {
+		m.viewingJob = 0
+		switch {
+		case m.running:
+			return m.showLive()
+		case m.lastText != "":
+			m = m.setOutput("$ " + m.lastText + "\n\n" + m.formatOutput(m.lastText, m.lastOutput))
+			m.viewport.GotoTop()
+		}
+		return m
+	}
+	j := m.jobs[i-1]
+	m.viewingJob = j.id
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("showing job %d (Ctrl-C stops it, J for the list)", j.id)
+	return m.showJob(j)
+}
+
+// viewedJob returns the background job shown in the output pane, if any.
+func (m model) viewedJob() *job {
+	for _, j := range m.jobs {
+		if j.id == m.viewingJob {
+			return j
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// looksLikeJSON reports whether output should be treated as JSON, either
+// because it starts like a JSON document or because --json was requested.
+func looksLikeJSON(cmdText, output string) bool {
+	trimmed := strings.TrimSpace(output)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return true
+	}
+	for _, f := range strings.Fields(cmdText) {
+		if f == "--json" || f == "-j" {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightJSON pretty-prints and colors a JSON document. ok is false when
+// the input does not parse, in which case callers should show it raw.
+func (s styles) highlightJSON(raw string) (string, bool) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(raw)), "", "  "); err != nil {
+		return raw, false
+	}
+	src := buf.String()
+
+	var b strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"':
+			end := scanJSONString(src, i)
+			tok := src[i:end]
+			if isJSONKey(src, end) {
+				b.WriteString(s.jsonKey.Render(tok))
+			} else {
+				b.WriteString(s.jsonString.Render(tok))
+			}
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(src) && strings.IndexByte("0123456789.eE+-", src[end]) >= 0 {
+				end++
+			}
+			b.WriteString(s.jsonNumber.Render(src[i:end]))
+			i = end
+		case c == 't' || c == 'f' || c == 'n':
+			end := i
+			for end < len(src) && src[end] >= 'a' && src[end] <= 'z' {
+				end++
+			}
+			b.WriteString(s.jsonLiteral.Render(src[i:end]))
+			i = end
+		case strings.IndexByte("{}[]:,", c) >= 0:
+			b.WriteString(s.jsonPunct.Render(string(c)))
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), true
+}
+
+// scanJSONString returns the index just past the string literal starting
+// at src[start], honoring backslash escapes.
+func scanJSONString(src string, start int) int {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+func isJSONKey(src string, end int) bool {
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return end < len(src) && src[end] == ':'
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// keyHelp documents one binding. The bottom bar shows the entries with a
+// bar label; the help overlay lists them all and shows the long text of
+// the highlighted one.
+type keyHelp struct {
+	section string
+	keys    string
+	short   string
+	long    string
+	bar     string // label in the bottom bar, "" to leave it out
+}
+
+var keymap = []keyHelp{
+	{"Navigation", "←/→", "switch category", "Move to the previous or next category (h/l also work). While a filter is active, categories without matches are skipped.", "category"},
+	{"Navigation", "↑/↓", "move selection", "Move the command selection (j/k also work). With autoForm set, landing on a command with required fields opens its form.", "command"},
+	{"Navigation", "1..9", "jump to category", "Jump straight to category N in the order shown in the left pane.", ""},
+	{"Navigation", "ctrl+p", "find category", "Open a fuzzy list of categories; type part of a name and press Enter to jump to it.", ""},
+	{"Navigation", "tab", "cycle focus", "Cycle focus between the categories, commands and output panes; Shift+Tab goes backwards. Focusing the output enables line-cursor keys like j/k, y and gg/G.", "focus"},
+	{"Running", "enter", "run selection", "Run the selected command, or open its form when it needs input.", "run"},
+	{"Running", ":", "command mode", "Type any devtunnel command (without the devtunnel prefix). Prefix with ! for a shell command when allowShell is set; Ctrl+S saves the line as a favorite.", "raw cmd"},
+	{"Running", "f", "inline flags", "Type flags for a command whose only input is flags (e.g. list --all) and run it with Enter.", ""},
+	{"Running", "r", "rerun", "Run the last command again, from the result cache while it is fresh.", "rerun"},
+	{"Running", "D", "rerun with diff", "Run the last command again and show which output lines were added or removed since the previous run; the full output shows when there is nothing to compare with.", ""},
+	{"Running", "R", "retry failed", "Run the last command again only if it failed, e.g. after signing in.", ""},
+	{"Running", "F", "force refresh", "Run the last command again, bypassing the result cache.", ""},
+	{"Running", "w", "watch", "Re-run the selected command (or the last one, when the selection needs input) every N seconds until any key is pressed.", ""},
+	{"Running", "&", "background", "Run the selected command as a background job, or move the running command to the background so another can run.", ""},
+	{"Running", "J", "jobs", "Choose whether the output pane shows the foreground result or a background job.", ""},
+	{"Running", "ctrl+c", "interrupt", "Interrupt the running command (or the background job being shown). Quits when nothing is running.", ""},
+	{"Commands", "/", "filter", "Filter the command list as you type. In the prompt, Ctrl+R toggles regex, Ctrl+A searches all categories and Ctrl+T cycles a tag filter.", "filter"},
+	{"Commands", "a", "advanced", "Show or hide advanced commands such as delete-all and echo.", ""},
+	{"Commands", "c", "compact", "Show only command names in the list.", ""},
+	{"Commands", "I", "catalog index", "Show each command's position in its category, which stays the same while filtering or reordering.", ""},
+	{"Commands", "O", "order", "Cycle the order of the current category: built-in, reversed, alphabetical.", ""},
+	{"Commands", "*", "favorite", "Mark the selected command as favorite N; alt+N runs it from anywhere.", ""},
+	{"Commands", "e", "wrap details", "Wrap the selected: and example: lines under the list instead of cutting them off.", ""},
+	{"Commands", "i", "inspector", "Show the exact argv passed to devtunnel, updated while typing.", ""},
+	{"Commands", "A", "command preview", "Show the command Enter would run in the bottom bar instead of these hints.", ""},
+	{"Commands", "K", "cluster", "Pick the cluster passed as --cluster; p in the picker measures latency to each.", ""},
+	{"Commands", "S", "safe mode", "Lock destructive and shell commands so they cannot run.", ""},
+	{"Output", "u/d", "scroll output", "Scroll the output half a page up or down from any pane (PgUp/PgDn also work).", "output scroll"},
+	{"Output", "gg/G", "top/bottom", "With the output focused, jump to the first or last line. In the command list, g and G select the first and last command.", ""},
+	{"Output", "<N>%", "jump to percent", "With the output focused, type a number and % to jump that far into the output, e.g. 50%.", ""},
+	{"Output", "<N>j/k", "count motions", "With the output focused, a number before j or k moves that many lines, and before G jumps to that line, e.g. 5j or 40G.", ""},
+	{"Output", "=", "relative numbers", "With the output focused, number lines relative to the cursor so the count for j/k can be read off; the cursor line shows its own number.", ""},
+	{"Output", "/ n N", "search output", "With the output focused, / searches; n and N move to the next and previous matching line.", ""},
+	{"Output", "] [", "links", "With the output focused, select the next or previous URL; Enter or o opens it in the browser.", ""},
+	{"Output", ">", "capture token", "With the output focused, capture the tunnel id of a list row, the selected link or the first word of the line; Ctrl+O inserts it in a form field or command mode.", ""},
+	{"Output", "y", "copy line", "With the output focused, copy the line under the cursor.", ""},
+	{"Output", "v", "select lines", "With the output focused, start selecting lines at the cursor; j/k, counts and gg/G extend the selection, y copies it and Esc or v cancels.", ""},
+	{"Output", "Y", "copy Markdown", "Copy the latest result as a Markdown code block, or write it to a file without a clipboard.", ""},
+	{"Output", "X", "export raw", "Write the exact bytes of the latest result (in the shown stream) to a file as base64 or hex, for output the pane cannot display.", ""},
+	{"Output", "z", "zoom", "Show the output pane full screen; any navigation key restores the layout.", ""},
+	{"Output", "#", "paged", "Show the output a page at a time; n/p turn pages.", ""},
+	{"Output", "s", "streams", "Cycle between combined, stdout-only and stderr-only views of the latest result.", ""},
+	{"Output", "t", "relative times", "Show timestamps in results as relative times like 2 days ago.", ""},
+	{"Output", "T", "arrival times", "Prefix streamed lines with the time they arrived.", ""},
+	{"Output", "m", "pin", "Pin the latest result (or the cursor line) above the output.", ""},
+	{"Output", "b/B", "diff", "b sets the latest output as the baseline; B shows the latest output as a diff against it.", ""},
+	{"Output", "M '", "bookmarks", "M names the current scroll position; ' jumps back to a bookmark.", ""},
+	{"Output", "U", "copy curl", "Copy curl -i for the selected URL, a URL on the cursor line, or the first tunnel endpoint in the output.", ""},
+	{"Output", "C", "copy connect", "Copy devtunnel connect for the tunnel under the cursor, the hosted tunnel or the default tunnel.", ""},
+	{"Session", "L", "session log", "Review the commands run this session; Tab switches between the order they ran and grouped by command (list, show, ...), and Enter shows an entry's output.", ""},
+	{"Session", "E", "export log", "Write every command of the session with timestamps, output and exit code to a file.", ""},
+	{"Session", "W", "working dir", "Set the directory commands run in.", ""},
+	{"Session", "P", "poll", "Pause or resume background tunnel polling.", ""},
+	{"Session", "ctrl+g", "global nav", "Switch whether j/k, g/G and the other navigation keys always move the command list, or act on the focused pane (the output cursor when the output is focused). The header shows nav:global in the first mode.", ""},
+	{"Session", "x", "swap panes", "Swap the command and output panes.", ""},
+	{"Session", "H", "hide categories", "Collapse the categories pane so commands and output get its width; the command pane title names the category and number keys still switch.", ""},
+	{"Session", "-", "minimize", "Shrink to a single status line; -, Enter or Esc expands again.", ""},
+	{"Session", "?", "help", "Show this list.", "help"},
+	{"Session", "q", "quit", "Quit devtunnel-tui.", "quit"},
+}
+
+// barHints renders the bottom bar entries of the keymap.
+func (m model) barHints() []string {
+	var out []string
+	for _, k := range keymap {
+		if k.bar == "" {
+			continue
+		}
+		hint := m.styles.hotkey.Render(k.keys) + " " + k.bar
+		if k.keys == "r" {
+			hint += m.lastRunHint()
+		}
+		out = append(out, hint)
+	}
+	return out
+}
+
+func (m model) updateHelp(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "esc", "q", "?":
+		m.helpOpen = false
+	case "up", "k":
+		m.helpIdx = max(0, m.helpIdx-1)
+	case "down", "j":
+		m.helpIdx = min(len(keymap)-1, m.helpIdx+1)
+	case "g", "home":
+		m.helpIdx = 0
+	case "G", "end":
+		m.helpIdx = len(keymap) - 1
+	}
+	return m, nil
+}
+
+// renderHelp lists the keymap around the highlighted entry, with its full
+// description in a box at the bottom.
+func (m model) renderHelp(width, height int) string {
+	sel := keymap[m.helpIdx]
+	tip := ansi.Wrap(sel.long, max(20, width-6), "")
+	tipLines := strings.Count(tip, "\n") + 1
+	rows := max(3, height-tipLines-6)
+	start := max(0, min(m.helpIdx-rows/2, len(keymap)-rows))
+
+	var b strings.Builder
+	b.WriteString(m.styles.paneTitle.Render("Keys"))
+	b.WriteString("\n")
+	section := ""
+	for i := start; i < min(len(keymap), start+rows); i++ {
+		k := keymap[i]
+		label := ""
+		if k.section != section || i == start {
+			label = k.section
+		}
+		section = k.section
+		line := padRight(label, 11) + padRight(k.keys, 8) + k.short
+		if i == m.helpIdx {
+			b.WriteString(m.styles.selected.Render(line))
+		} else {
+			b.WriteString(m.styles.normal.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.hotkey.Render(sel.keys) + "  " + sel.short + "\n")
+	b.WriteString(tip)
+	return m.styles.pane.Width(width).Height(height).BorderForeground(lipgloss.Color("39")).Render(b.String())
+}
+
+func padRight(s string, width int) string {
+	if n := ansi.StringWidth(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s + " "
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// latencyTimeout bounds each cluster's round trip.
+const latencyTimeout = 5 * time.Second
+
+// clusterPing is one measured round trip to a cluster's service endpoint.
+type clusterPing struct {
+	rtt time.Duration
+	err error
+}
+
+type latencyMsg struct {
+	results map[string]clusterPing
+}
+
+// measureLatencyCmd times an HTTPS request to every cluster's service URI
+// in parallel. Any HTTP response counts: only the round trip matters.
+func (m model) measureLatencyCmd() tea.Cmd {
+	uris := m.clusterURIs
+	return func() tea.Msg {
+		client := &http.Client{Timeout: latencyTimeout}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		results := map[string]clusterPing{}
+		for id, uri := range uris {
+			wg.Add(1)
+			go func(id, uri string) {
+				defer wg.Done()
+				p := pingURI(client, uri)
+				mu.Lock()
+				results[id] = p
+				mu.Unlock()
+			}(id, uri)
+		}
+		wg.Wait()
+		return latencyMsg{results: results}
+	}
+}
+
+func pingURI(client *http.Client, uri string) clusterPing {
+	ctx, cancel := context.WithTimeout(context.Background(), latencyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return clusterPing{err: err}
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return clusterPing{err: err}
+	}
+	resp.Body.Close()
+	return clusterPing{rtt: time.Since(start)}
+}
+
+// measureLatency starts a latency check unless one is already running.
+func (m model) measureLatency() (model, tea.Cmd) {
+	if len(m.clusterURIs) == 0 {
+		m.statusErr = true
+		m.statusText = "no clusters to measure (run clusters first)"
+		return m, nil
+	}
+	if m.measuring {
+		return m, nil
+	}
+	m.measuring = true
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("measuring latency to %d clusters", len(m.clusterURIs))
+	return m, m.measureLatencyCmd()
+}
+
+// onLatency stores measured latencies and refreshes whatever shows them.
+func (m model) onLatency(msg latencyMsg) model {
+	m.measuring = false
+	m.clusterLatency = msg.results
+	m.latencyAt = time.Now()
+	m.statusErr = false
+	m.statusText = "latency measured"
+	if ids := m.clustersByLatency(); len(ids) > 0 && m.clusterLatency[ids[0]].err == nil {
+		m.statusText += ", fastest: " + ids[0]
+	}
+	if m.pickerMode && m.picker.kind == pickCluster {
+		m = m.openClusterPicker()
+	}
+	if f := strings.Fields(m.lastText); !m.running && m.viewingJob == 0 && len(f) > 1 && f[1] == "clusters" {
+		m = m.setOutput("$ " + m.lastText + "\n\n" + m.formatOutput(m.lastText, m.lastOutput))
+	}
+	return m
+}
+
+// clustersByLatency orders the known clusters fastest first; unmeasured and
+// unreachable clusters follow in their listed order.
+func (m model) clustersByLatency() []string {
+	ids := slices.Clone(m.clusters)
+	rank := func(id string) time.Duration {
+		p, ok := m.clusterLatency[id]
+		if !ok || p.err != nil {
+			return time.Duration(1<<63 - 1)
+		}
+		return p.rtt
+	}
+	slices.SortStableFunc(ids, func(a, b string) int {
+		ra, rb := rank(a), rank(b)
+		switch {
+		case ra < rb:
+			return -1
+		case ra > rb:
+			return 1
+		}
+		return 0
+	})
+	return ids
+}
+
+func (m model) latencyLabel(id string) string {
+	p, ok := m.clusterLatency[id]
+	switch {
+	case !ok:
+		return ""
+	case p.err != nil:
+		return "unreachable"
+	}
+	return p.rtt.Round(time.Millisecond).String()
+}
+
+// renderClusters lays out `devtunnel clusters` as a table with the measured
+// latency, fastest first. It falls back to the raw output when nothing
+// parses.
+func (m model) renderClusters(output string) string {
+	rows := parseClusters(output)
+	if len(rows) == 0 {
+		return output
+	}
+	uris := map[string]string{}
+	width := len("Cluster")
+	for _, r := range rows {
+		uris[r.id] = r.uri
+		width = max(width, len(r.id))
+	}
+	ids := make([]string, len(rows))
+	for i, r := range rows {
+		ids[i] = r.id
+	}
+	m.clusters = ids // sort this output's rows, not the remembered list
+	var b strings.Builder
+	b.WriteString(m.styles.paneTitle.Render(fmt.Sprintf("%-*s  %-11s  %s", width, "Cluster", "Latency", "URI")))
+	b.WriteString("\n")
+	for _, id := range m.clustersByLatency() {
+		lat := fmt.Sprintf("%-11s", valueOr(m.latencyLabel(id), "-"))
+		if p, ok := m.clusterLatency[id]; ok && p.err != nil {
+			lat = m.styles.err.Render(lat)
+		}
+		fmt.Fprintf(&b, "%-*s  %s  %s\n", width, id, lat, uris[id])
+	}
+	if m.latencyAt.IsZero() {
+		b.WriteString(m.styles.dim.Render("\nK then p measures latency to each cluster"))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
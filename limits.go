@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// limitRow is one quota parsed from `devtunnel limits`. used and max are
+// only meaningful when hasUsage is set.
+type limitRow struct {
+	name     string
+	value    string
+	used     float64
+	max      float64
+	hasUsage bool
+}
+
+var (
+	// "Tunnels: 3/10", "Tunnels : 3 of 10", "Tunnels  3 / 10"
+	limitUsageRe = regexp.MustCompile(`^(.+?)(?:\s*:\s*|\s{2,})(\d+(?:\.\d+)?)\s*(?:/|of)\s*(\d+(?:\.\d+)?)\b(.*)$`)
+	// table rows: "Tunnels   3   10"
+	limitColumnsRe = regexp.MustCompile(`^(.+?)\s{2,}(\d+(?:\.\d+)?)\s{2,}(\d+(?:\.\d+)?)\s*$`)
+	// "Max ports: 10" or "Max ports   10"
+	limitPairRe = regexp.MustCompile(`^(.+?)(?:\s*:\s*|\s{2,})(\S.*)$`)
+)
+
+// parseLimits reads quota lines in the formats above, skipping headers and
+// blank lines. ok is false when no usable row was found.
+func parseLimits(output string) (rows []limitRow, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Trim(line, "-=─ ") == "" {
+			continue
+		}
+		if m := limitUsageRe.FindStringSubmatch(line); m != nil {
+			rows = append(rows, usageRow(m[1], m[2], m[3], strings.TrimSpace(m[4])))
+			continue
+		}
+		if m := limitColumnsRe.FindStringSubmatch(line); m != nil {
+			rows = append(rows, usageRow(m[1], m[2], m[3], ""))
+			continue
+		}
+		if m := limitPairRe.FindStringSubmatch(line); m != nil && hasDigit(m[2]) {
+			rows = append(rows, limitRow{name: strings.TrimSpace(m[1]), value: strings.TrimSpace(m[2])})
+		}
+	}
+	return rows, len(rows) > 0
+}
+
+func usageRow(name, used, limit, unit string) limitRow {
+	u, _ := strconv.ParseFloat(used, 64)
+	l, _ := strconv.ParseFloat(limit, 64)
+	value := used + " / " + limit
+	if unit != "" {
+		value += " " + unit
+	}
+	return limitRow{name: strings.TrimSpace(name), value: value, used: u, max: l, hasUsage: l > 0}
+}
+
+func hasDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
+
+// renderLimits lays out rows in two aligned columns, coloring usage that is
+// at least 75% (warn) or 90% (err) of its limit. It falls back to the raw
+// output when nothing parses.
+func (s styles) renderLimits(output string) string {
+	rows, ok := parseLimits(output)
+	if !ok {
+		return output
+	}
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r.name))
+	}
+	var b strings.Builder
+	b.WriteString(s.paneTitle.Render(fmt.Sprintf("%-*s  %s", width, "Limit", "Value")))
+	b.WriteString("\n")
+	for _, r := range rows {
+		value := r.value
+		if r.hasUsage {
+			pct := r.used / r.max * 100
+			style := s.ok
+			switch {
+			case pct >= 90:
+				style = s.err
+			case pct >= 75:
+				style = s.warn
+			}
+			value = style.Render(fmt.Sprintf("%s (%.0f%%)", r.value, pct))
+		}
+		fmt.Fprintf(&b, "%-*s  %s\n", width, r.name, value)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
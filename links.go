@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// urlRe matches http(s) URLs; trailing punctuation is trimmed separately.
+var urlRe = regexp.MustCompile(`https?://[^\s"'<>()\[\]{}\x1b]+`)
+
+// outputLink is a URL found in the output pane, by line.
+type outputLink struct {
+	line int
+	url  string
+}
+
+func trimURL(u string) string {
+	return strings.TrimRight(u, ".,;:!?")
+}
+
+// linkify underlines the URLs in each line and returns them in order.
+func (s styles) linkify(lines []string) ([]string, []outputLink) {
+	var links []outputLink
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		for _, u := range urlRe.FindAllString(ansi.Strip(line), -1) {
+			links = append(links, outputLink{line: i, url: trimURL(u)})
+		}
+		out[i] = urlRe.ReplaceAllStringFunc(line, func(u string) string {
+			t := trimURL(u)
+			return s.link.Render(t) + u[len(t):]
+		})
+	}
+	return out, links
+}
+
+// stepLink moves the link cursor to the next or previous URL, wrapping
+// around, and puts the line cursor on it.
+func (m model) stepLink(delta int) model {
+	if len(m.links) == 0 {
+		m.statusErr = true
+		m.statusText = "no links in output"
+		return m
+	}
+	if m.linkIdx < 0 {
+		m.linkIdx = 0
+		if delta < 0 {
+			m.linkIdx = len(m.links) - 1
+		}
+	} else {
+		m.linkIdx = (m.linkIdx + delta + len(m.links)) % len(m.links)
+	}
+	l := m.links[m.linkIdx]
+	m = m.setCursor(l.line)
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("link %d/%d: %s (Enter or o to open)", m.linkIdx+1, len(m.links), l.url)
+	return m
+}
+
+// openSelectedLink opens the link under the link cursor in the default
+// browser, provided the line cursor is still on it.
+func (m model) openSelectedLink() (model, bool) {
+	if m.linkIdx < 0 || m.linkIdx >= len(m.links) || m.links[m.linkIdx].line != m.outCursor {
+		return m, false
+	}
+	u := m.links[m.linkIdx].url
+	if err := openBrowser(u); err != nil {
+		m.statusErr = true
+		m.statusText = "could not open " + u + ": " + err.Error()
+		return m, true
+	}
+	m.statusErr = false
+	m.statusText = "opened " + u
+	return m, true
+}
+
+// openBrowser hands url to the platform's opener without waiting for it.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
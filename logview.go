@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// logGroup names the command an entry belongs to in the grouped view: the
+// devtunnel subcommand (list, show, ...) or the program for other commands.
+func (e logEntry) logGroup() string {
+	switch {
+	case len(e.args) > 1 && e.args[0] == "devtunnel":
+		return e.args[1]
+	case len(e.args) > 0:
+		return e.args[0]
+	default:
+		return e.cmdText
+	}
+}
+
+// logOrder returns session log indices in display order: as run, or
+// grouped by command with groups in order of their first run.
+func (m model) logOrder() []int {
+	order := make([]int, 0, len(m.sessionLog))
+	if !m.logGrouped {
+		for i := range m.sessionLog {
+			order = append(order, i)
+		}
+		return order
+	}
+	var groups []string
+	byGroup := map[string][]int{}
+	for i, e := range m.sessionLog {
+		g := e.logGroup()
+		if _, ok := byGroup[g]; !ok {
+			groups = append(groups, g)
+		}
+		byGroup[g] = append(byGroup[g], i)
+	}
+	for _, g := range groups {
+		order = append(order, byGroup[g]...)
+	}
+	return order
+}
+
+// openLog shows the session log overlay on the latest entry.
+func (m model) openLog() model {
+	if len(m.sessionLog) == 0 {
+		m.statusErr = true
+		m.statusText = "session log is empty"
+		return m
+	}
+	m.logOpen = true
+	m.logIdx = len(m.sessionLog) - 1
+	if m.logGrouped {
+		m.logIdx = max(0, slices.Index(m.logOrder(), len(m.sessionLog)-1))
+	}
+	return m
+}
+
+func (m model) updateLog(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	order := m.logOrder()
+	switch k.String() {
+	case "esc", "q", "L":
+		m.logOpen = false
+	case "up", "k":
+		m.logIdx = max(0, m.logIdx-1)
+	case "down", "j":
+		m.logIdx = min(len(order)-1, m.logIdx+1)
+	case "g", "home":
+		m.logIdx = 0
+	case "G", "end":
+		m.logIdx = len(order) - 1
+	case "tab":
+		// Keep the same entry selected across the switch.
+		sel := order[m.logIdx]
+		m.logGrouped = !m.logGrouped
+		m.logIdx = max(0, slices.Index(m.logOrder(), sel))
+	case "enter":
+		e := m.sessionLog[order[m.logIdx]]
+		m.logOpen = false
+		m = m.setOutput(fmt.Sprintf("$ %s  [session log, %s, %s]\n\n%s", e.cmdText, e.exitStatus(), e.start.Format(time.TimeOnly), strings.TrimRight(e.output, "\n")))
+		m.outNotLatest = true
+		m.viewport.GotoTop()
+		m.statusErr = false
+		m.statusText = "showing logged output of: " + e.cmdText
+	}
+	return m, nil
+}
+
+// renderLog lists the session log around the selected entry, under a
+// header per command in the grouped view, with the start of the selected
+// entry's output below.
+func (m model) renderLog(width, height int) string {
+	order := m.logOrder()
+	sel := m.sessionLog[order[m.logIdx]]
+	preview := strings.Split(strings.TrimRight(sel.output, "\n"), "\n")
+	preview = preview[:min(len(preview), max(3, height/3))]
+	rows := max(3, height-len(preview)-6)
+	start := max(0, min(m.logIdx-rows/2, len(order)-rows))
+
+	counts := map[string][2]int{} // runs, failures
+	for _, e := range m.sessionLog {
+		c := counts[e.logGroup()]
+		c[0]++
+		if e.err != nil {
+			c[1]++
+		}
+		counts[e.logGroup()] = c
+	}
+
+	view := "as run"
+	if m.logGrouped {
+		view = "grouped by command"
+	}
+	var b strings.Builder
+	b.WriteString(m.styles.paneTitle.Render(fmt.Sprintf("Session log, %d run(s), %s", len(m.sessionLog), view)))
+	b.WriteString("\n")
+	group := ""
+	for i := start; i < min(len(order), start+rows); i++ {
+		e := m.sessionLog[order[i]]
+		if m.logGrouped && (e.logGroup() != group || i == start) {
+			group = e.logGroup()
+			c := counts[group]
+			b.WriteString(m.styles.hotkey.Render(fmt.Sprintf("%s  (%d run(s), %d failed)", group, c[0], c[1])))
+			b.WriteString("\n")
+		}
+		line := fmt.Sprintf("%s  %-10s %8s  %s", e.start.Format(time.TimeOnly), e.exitStatus(), e.end.Sub(e.start).Round(time.Millisecond), e.cmdText)
+		if m.logGrouped {
+			line = "  " + line
+		}
+		line = ansi.Truncate(line, max(10, width-4), "…")
+		if i == m.logIdx {
+			b.WriteString(m.styles.selected.Render(line))
+		} else {
+			b.WriteString(m.styles.normal.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	for _, l := range preview {
+		b.WriteString(m.styles.dim.Render(ansi.Truncate(l, max(10, width-4), "…")))
+		b.WriteString("\n")
+	}
+	return m.styles.pane.Width(width).Height(height).BorderForeground(lipgloss.Color("39")).Render(b.String())
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"errors"
+	"flag"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +18,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type commandItem struct {
@@ -22,8 +26,24 @@ type commandItem struct {
 	description string
 	baseArgs    []string
 	required    []string
-	optional    string
-	example     string
+	// params names the {placeholders} in baseArgs; they lead required and
+	// are substituted instead of appended
+	params []string
+	// placeholders holds an example value per required arg, by index
+	placeholders []string
+	multi        []multiField
+	aliases      []string
+	optional     string
+	example      string
+	clusterFlag  bool
+	cacheable    bool
+	advanced     bool
+	destructive  bool // locked in safe mode
+	prompts      promptHandling
+	sensitive    []string                      // field labels masked as secrets beyond sensitiveLabelRe
+	validate     map[string]func(string) error // by field label, checked before running
+	wizard       []wizardField
+	workflow     *workflowConfig
 }
 
 type commandCategory struct {
@@ -32,13 +52,20 @@ type commandCategory struct {
 }
 
 type runStartedMsg struct {
-	cmdText string
+	cmdText   string
+	args      []string
+	interrupt context.CancelFunc
+	ch        <-chan tea.Msg
 }
 
 type runFinishedMsg struct {
 	cmdText string
-	output  string
+	args    []string
+	output  string // stdout and stderr interleaved
+	stdout  string
+	stderr  string
 	err     error
+	ch      <-chan tea.Msg // stream it came from, nil if not streamed
 }
 
 type styles struct {
@@ -54,8 +81,17 @@ type styles struct {
 	err         lipgloss.Style
 	hotkey      lipgloss.Style
 	cmdline     lipgloss.Style
+	shellLine   lipgloss.Style
+	defaultMark lipgloss.Style
+	cursorLine  lipgloss.Style
+	link        lipgloss.Style
 	statusBar   lipgloss.Style
 	focusBorder lipgloss.Style
+	jsonKey     lipgloss.Style
+	jsonString  lipgloss.Style
+	jsonNumber  lipgloss.Style
+	jsonLiteral lipgloss.Style
+	jsonPunct   lipgloss.Style
 }
 
 type model struct {
@@ -65,34 +101,182 @@ type model struct {
 
 	styles  styles
 	spinner spinner.Model
+	procs   *procTable
+
+	cfg      config
+	state    uiState
+	warnings []string
+	aliases  map[string][]string // alias -> devtunnel subcommand args
+	workDir  string              // empty runs commands in the current directory
+	secrets  secretSet
+	cache    resultCache
 
 	devtunnelFound bool
 	running        bool
 	statusText     string
 	statusErr      bool
+	runStart       time.Time
+	interrupt      context.CancelFunc // stops the running command
+	runCh          <-chan tea.Msg     // stream of the running command
+	runText        string
+	runArgs        []string
+	liveLines      []stampedLine
+	liveHeader     string
+	timestamps     bool // prefix streamed lines with their arrival time
+	progress       float64
+	hasProgress    bool
 
 	categories []commandCategory
 	catIdx     int
 	cmdIdx     int
-	focusPane  int // visual hint only
-
-	viewport viewport.Model
+	focusPane  int // 0 categories, 1 commands, 2 output
+	count      string
+	pendingG   bool
+
+	compactCommands bool
+	wrapFooter      bool
+	paged           bool
+	minimized       bool
+	humanTimes      bool
+	showIndices     bool
+	helpOpen        bool
+	logOpen         bool
+	logIdx          int
+	logGrouped      bool // session log overlay grouped by command
+	helpIdx         int  // highlighted keymap entry in the help overlay
+	searchQuery     string
+	matches         []int // output lines containing searchQuery
+	matchIdx        int   // index into matches, -1 before the first jump
+	safeMode        bool
+	showAdvanced    bool
+	showInspector   bool
+	zoomed          bool
+
+	viewport  viewport.Model
+	outLines  []string
+	outRaw    []string // undecorated outLines for copying, when they differ
+	outCursor int
+	links     []outputLink
+	linkIdx   int // selected entry of links, -1 for none
 
 	filterMode  bool
 	filterInput textinput.Model
+	filterRegex bool
+	filterRe    *regexp.Regexp
+	filterErr   string
+	filterTag   string // only commands with this tag, "" for any
+	filterAll   bool   // list matches from every category
 
 	cmdMode  bool
 	cmdInput textinput.Model
 
+	flagsMode  bool
+	flagsInput textinput.Model
+	flagsCmd   *commandItem
+
+	promptMode  bool
+	promptKind  promptKind
+	promptInput textinput.Model
+
+	confirmMode bool
+	confirmArgs []sourcedArg
+
+	loginPrompt  bool
+	logoutPrompt bool
+	logoutClear  bool // clear cached tunnel data once logout succeeds
+	login        loginState
+	loginUser    string
+
+	pasteSteps [][]string // pasted commands awaiting confirmation
+
 	formMode   bool
 	formTitle  string
 	formCmd    *commandItem
 	formLabels []string
 	formInputs []textinput.Model
+	formChecks []checklist
 	formIndex  int
-
-	lastCmd    []string
-	lastOutput string
+	formAuto   bool // opened by autoForm rather than Enter
+
+	pickerMode bool
+	picker     picker
+
+	cluster            string
+	clusters           []string
+	clusterURIs        map[string]string
+	clusterLatency     map[string]clusterPing
+	latencyAt          time.Time // when clusterLatency was measured
+	measuring          bool
+	clusterPickPending bool
+
+	defaultTunnel string
+	tunnelCount   int
+	haveCount     bool
+	pollPaused    bool
+
+	watching   bool
+	watchParts []string
+	watchEvery time.Duration
+	watchIter  int
+	watchGen   int
+
+	// runSubcmd names the subcommand of the current run; listenAddr is the
+	// address a running echo server reported and hostedTunnel the tunnel
+	// the latest `host` run served.
+	runSubcmd    string
+	listenAddr   string
+	hostedTunnel string
+
+	// jobs are commands running in the background; viewingJob is the id of
+	// the one shown in the output pane, 0 for the foreground result.
+	jobs           []*job
+	nextJobID      int
+	viewingJob     int
+	rerunBase      *rerunBase // previous result, while a D rerun runs
+	captured       string     // token taken from the output with >, inserted by Ctrl+O
+	nextBackground bool       // the next run started becomes a job
+
+	lastCmd   []string
+	failedCmd []string // latest run when it failed, nil after a success
+	lastRunAt time.Time
+	cachedAt  time.Time // when the shown result was cached, zero if it ran live
+	// outNotLatest is set while the output shows something other than the
+	// latest result, such as a session log entry; setOutput clears it
+	outNotLatest bool
+	// redrawPending is set while a liveRedrawMsg is on its way
+	redrawPending bool
+	visual        bool // line selection in the output, from visualStart to outCursor
+	visualStart   int
+	lastOutput    string
+	lastStdout    string
+	lastStderr    string
+	// haveStreams is set when lastStdout/lastStderr belong to the latest
+	// result; workflows only keep combined output.
+	haveStreams bool
+	outStream   outputStream
+	lastText    string
+	outcomes    []bool // most recent last, capped at maxOutcomes
+	sessionLog  []logEntry
+	cmdLog      *commandLog
+
+	baseline    string
+	baselineCmd string
+
+	pinned     string
+	pinnedFrom string
+
+	bookmarks []bookmark
+
+	workflow *workflowRun
+
+	wizard      *wizardRun
+	wizardInput textinput.Model
+
+	whatsNew string // changelog excerpt shown after an upgrade
+
+	lastInput      time.Time
+	prefetchGen    int
+	prefetchCancel context.CancelFunc
 }
 
 func newStyles() styles {
@@ -109,8 +293,17 @@ func newStyles() styles {
 		err:         lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
 		hotkey:      lipgloss.NewStyle().Foreground(lipgloss.Color("121")).Bold(true),
 		cmdline:     lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Background(lipgloss.Color("236")).Padding(0, 1),
+		shellLine:   lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Background(lipgloss.Color("88")).Padding(0, 1),
+		defaultMark: lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true),
+		cursorLine:  lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Background(lipgloss.Color("238")),
+		link:        lipgloss.NewStyle().Foreground(lipgloss.Color("75")).Underline(true),
 		statusBar:   lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Background(lipgloss.Color("236")).Padding(0, 1),
 		focusBorder: lipgloss.NewStyle().BorderForeground(lipgloss.Color("39")),
+		jsonKey:     lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
+		jsonString:  lipgloss.NewStyle().Foreground(lipgloss.Color("150")),
+		jsonNumber:  lipgloss.NewStyle().Foreground(lipgloss.Color("215")),
+		jsonLiteral: lipgloss.NewStyle().Foreground(lipgloss.Color("176")),
+		jsonPunct:   lipgloss.NewStyle().Foreground(lipgloss.Color("244")),
 	}
 }
 
@@ -119,15 +312,16 @@ func catalog() []commandCategory {
 		{
 			name: "Tunnels",
 			commands: []commandItem{
-				{name: "list", description: "List tunnels", baseArgs: []string{"list"}, optional: "flags", example: "list --all"},
-				{name: "show", description: "Show tunnel details", baseArgs: []string{"show"}, required: []string{"tunnel-id"}},
-				{name: "create", description: "Create a tunnel", baseArgs: []string{"create"}, required: []string{"tunnel-id"}, optional: "flags"},
-				{name: "update", description: "Update tunnel properties", baseArgs: []string{"update"}, required: []string{"tunnel-id"}, optional: "flags"},
-				{name: "delete", description: "Delete a tunnel", baseArgs: []string{"delete"}, required: []string{"tunnel-id"}},
-				{name: "delete-all", description: "Delete all tunnels", baseArgs: []string{"delete-all"}},
-				{name: "set", description: "Set default tunnel", baseArgs: []string{"set"}, required: []string{"tunnel-id"}},
-				{name: "unset", description: "Clear default tunnel", baseArgs: []string{"unset"}},
-				{name: "token", description: "Issue tunnel access token", baseArgs: []string{"token"}, required: []string{"tunnel-id"}, optional: "flags"},
+				{name: "list", description: "List tunnels", baseArgs: []string{"list"}, optional: "flags", example: "list --all", clusterFlag: true, cacheable: true},
+				{name: "show", description: "Show tunnel details", baseArgs: []string{"show"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}, cacheable: true},
+				{name: "create", description: "Create a tunnel", baseArgs: []string{"create"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}, optional: "flags", clusterFlag: true},
+				{name: "create wizard", description: "Create a tunnel step by step", baseArgs: []string{"create"}, wizard: createWizard(), clusterFlag: true},
+				{name: "update", description: "Update tunnel properties", baseArgs: []string{"update"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}, optional: "flags"},
+				{name: "delete", description: "Delete a tunnel", baseArgs: []string{"delete"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}, destructive: true, prompts: promptHandling{forceFlag: "--force"}},
+				{name: "delete-all", description: "Delete all tunnels", baseArgs: []string{"delete-all"}, advanced: true, destructive: true, prompts: promptHandling{forceFlag: "--force"}},
+				{name: "set", description: "Set default tunnel", baseArgs: []string{"set"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}},
+				{name: "unset", description: "Clear default tunnel", baseArgs: []string{"unset"}, destructive: true},
+				{name: "token", description: "Issue tunnel access token", baseArgs: []string{"token"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}, multi: []multiField{{label: "scopes", flag: "--scopes", options: []string{"connect", "host", "manage"}}}, optional: "flags"},
 			},
 		},
 		{
@@ -140,25 +334,25 @@ func catalog() []commandCategory {
 		{
 			name: "Connections",
 			commands: []commandItem{
-				{name: "host", description: "Host a tunnel", baseArgs: []string{"host"}, optional: "tunnel-id and flags"},
-				{name: "connect", description: "Connect to tunnel", baseArgs: []string{"connect"}, required: []string{"tunnel-id"}, optional: "flags"},
+				{name: "host", description: "Host a tunnel", baseArgs: []string{"host"}, optional: "tunnel-id and flags", clusterFlag: true},
+				{name: "connect", description: "Connect to tunnel", baseArgs: []string{"connect"}, required: []string{"tunnel-id"}, placeholders: []string{"my-web-tunnel"}, optional: "flags"},
 			},
 		},
 		{
 			name: "User",
 			commands: []commandItem{
-				{name: "user login", description: "Authenticate user credentials", baseArgs: []string{"user", "login"}},
-				{name: "user logout", description: "Remove local credentials", baseArgs: []string{"user", "logout"}},
+				{name: "user login", description: "Authenticate user credentials", baseArgs: []string{"user", "login"}, prompts: promptHandling{interactive: true}},
+				{name: "user logout", description: "Remove local credentials", baseArgs: []string{"user", "logout"}, destructive: true},
 				{name: "user", description: "Run user subcommand", baseArgs: []string{"user"}, optional: "subcommand and args", example: "user show"},
 			},
 		},
 		{
 			name: "Diagnostics",
 			commands: []commandItem{
-				{name: "limits", description: "List user limits", baseArgs: []string{"limits"}},
-				{name: "clusters", description: "List clusters", baseArgs: []string{"clusters"}},
-				{name: "echo", description: "Run echo server", baseArgs: []string{"echo"}, required: []string{"protocol"}},
-				{name: "ping", description: "Ping remote echo server", baseArgs: []string{"ping"}, required: []string{"uri"}},
+				{name: "limits", description: "List user limits", baseArgs: []string{"limits"}, cacheable: true},
+				{name: "clusters", description: "List clusters", baseArgs: []string{"clusters"}, cacheable: true},
+				{name: "echo", description: "Run echo server until stopped", baseArgs: []string{"echo"}, multi: []multiField{{label: "protocol", options: []string{"http", "https", "tcp"}, single: true}}, optional: "flags", placeholders: []string{"", "-p 8080"}, advanced: true},
+				{name: "ping", description: "Ping remote echo server", baseArgs: []string{"ping"}, required: []string{"uri"}, placeholders: []string{"https://my-web-tunnel-8080.usw2.devtunnels.ms"}, validate: map[string]func(string) error{"uri": validateURI}},
 			},
 		},
 		{
@@ -170,43 +364,136 @@ func catalog() []commandCategory {
 	}
 }
 
-func initialModel() model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+// arrangeCategories applies the configured order and hidden set. Listed
+// categories come first, the rest keep their built-in order.
+func arrangeCategories(cats []commandCategory, cfg categoryConfig) ([]commandCategory, []string) {
+	var warnings []string
+	index := map[string]int{}
+	for i, c := range cats {
+		index[strings.ToLower(c.name)] = i
+	}
+	lookup := func(kind, name string) (int, bool) {
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("categories.%s: unknown category %q ignored", kind, name))
+		}
+		return i, ok
+	}
 
+	hidden := map[int]bool{}
+	for _, name := range cfg.Hidden {
+		if i, ok := lookup("hidden", name); ok {
+			hidden[i] = true
+		}
+	}
+
+	used := map[int]bool{}
+	out := make([]commandCategory, 0, len(cats))
+	for _, name := range cfg.Order {
+		if i, ok := lookup("order", name); ok && !used[i] && !hidden[i] {
+			used[i] = true
+			out = append(out, cats[i])
+		}
+	}
+	for i, c := range cats {
+		if !used[i] && !hidden[i] {
+			out = append(out, c)
+		}
+	}
+	return out, warnings
+}
+
+func initialModel() model {
 	filter := textinput.New()
 	filter.Placeholder = "filter commands"
 	filter.CharLimit = 120
 	filter.Prompt = "/ "
-	filter.Width = 40
 
 	cmd := textinput.New()
 	cmd.Placeholder = "type command after 'devtunnel'"
 	cmd.CharLimit = 500
 	cmd.Prompt = ": "
-	cmd.Width = 70
 
-	return model{
+	flags := textinput.New()
+	flags.Placeholder = "flags"
+	flags.CharLimit = 300
+
+	cfg, warnings := loadSettings()
+
+	s := spinner.New()
+	var spinWarnings []string
+	s.Spinner, spinWarnings = configSpinner(cfg.Spinner)
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	warnings = append(warnings, spinWarnings...)
+
+	categories, customWarnings := mergeCustomCategories(catalog(), cfg.CustomCategories)
+	warnings = append(warnings, customWarnings...)
+	if len(cfg.Workflows) > 0 {
+		wfCat, wfWarnings := workflowCategory(cfg.Workflows)
+		warnings = append(warnings, wfWarnings...)
+		if len(wfCat.commands) > 0 {
+			categories = append(categories, wfCat)
+		}
+	}
+	warnings = append(warnings, duplicateCommandWarnings(categories)...)
+	aliases, aliasWarnings := applyAliases(categories, cfg.Aliases)
+	warnings = append(warnings, aliasWarnings...)
+	categories, catWarnings := arrangeCategories(categories, cfg.Categories)
+	warnings = append(warnings, catWarnings...)
+	if len(categories) == 0 {
+		warnings = append(warnings, "no categories left to show: all are hidden in config")
+	}
+
+	state, err := loadState()
+	if err != nil {
+		warnings = append(warnings, "state: "+err.Error())
+	}
+
+	workDir, err := resolveWorkDir(cfg.WorkDir)
+	if err != nil {
+		warnings = append(warnings, "workDir: "+err.Error()+" (using current directory)")
+	}
+
+	cmdLog, err := openCommandLog(cfg.CommandLog, cfg.LogRetention)
+	if err != nil {
+		warnings = append(warnings, "commandLog: "+err.Error()+" (logging disabled)")
+	}
+
+	m := model{
 		styles:      newStyles(),
+		cfg:         cfg,
+		state:       state,
+		warnings:    warnings,
+		aliases:     aliases,
+		workDir:     workDir,
+		procs:       newProcTable(cfg.DevtunnelPath),
+		cmdLog:      cmdLog,
+		secrets:     secretSet{},
+		cache:       resultCache{},
 		spinner:     s,
-		categories:  catalog(),
+		categories:  categories,
 		statusText:  "checking devtunnel binary",
 		filterInput: filter,
 		cmdInput:    cmd,
+		flagsInput:  flags,
+		promptInput: newPromptInput(),
+		wizardInput: newPromptInput(),
 		focusPane:   1,
+		safeMode:    cfg.SafeMode,
 	}
+	return m.checkWhatsNew()
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(checkBinaryCmd(), m.spinner.Tick)
+	return tea.Batch(checkBinaryCmd(m.procs.program("devtunnel")), m.spinnerTick(), m.titleCmd("starting"))
 }
 
-func checkBinaryCmd() tea.Cmd {
+// checkBinaryCmd looks for bin, on PATH unless it is a path.
+func checkBinaryCmd(bin string) tea.Cmd {
 	return func() tea.Msg {
-		_, err := exec.LookPath("devtunnel")
+		_, err := exec.LookPath(bin)
 		if err != nil {
-			return runFinishedMsg{cmdText: "which devtunnel", output: "devtunnel not found in PATH", err: err}
+			return runFinishedMsg{cmdText: "which devtunnel", output: bin + " not found", err: err}
 		}
 		return runFinishedMsg{cmdText: "which devtunnel", output: "devtunnel detected", err: nil}
 	}
@@ -218,13 +505,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		if !m.ready {
-			m.viewport = viewport.New(max(20, msg.Width-64), max(8, msg.Height-10))
-			m.viewport.SetContent("Output will appear here")
+			m.viewport = viewport.New(0, 0)
+			m = m.setOutput(m.welcomeText())
 			m.ready = true
-		} else {
-			m.viewport.Width = max(20, msg.Width-64)
-			m.viewport.Height = max(8, msg.Height-10)
 		}
+		m = m.layoutViewport()
+		m = m.resizeInputs()
 
 	case spinner.TickMsg:
 		if m.running {
@@ -233,65 +519,361 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case clustersMsg:
+		pending := m.clusterPickPending
+		m.clusterPickPending = false
+		if msg.err != nil {
+			m.statusErr = true
+			m.statusText = "could not list clusters"
+			return m, nil
+		}
+		m = m.setClusters(msg.rows)
+		if pending {
+			m.statusText = "ready"
+			return m.openClusterPicker(), nil
+		}
+
+	case discoveredMsg:
+		return m.onDiscovered(msg), nil
+	case latencyMsg:
+		return m.onLatency(msg), nil
+	case safeModeBlockedMsg:
+		return m.onSafeModeBlocked(msg), nil
 	case runStartedMsg:
+		m = m.cancelPrefetch()
+		if m.nextBackground && m.workflow == nil && msg.ch != nil {
+			m.nextBackground = false
+			var j *job
+			m, j = m.addJob(msg.cmdText, msg.args, msg.ch, msg.interrupt, time.Now())
+			m.statusErr = false
+			m.statusText = fmt.Sprintf("job %d started in background: %s (J to list)", j.id, j.cmdText)
+			return m.logCommand("start", msg.cmdText), nil
+		}
+		m.nextBackground = false
 		m.running = true
+		m.interrupt = msg.interrupt
+		m.runCh = msg.ch
+		m.runText = msg.cmdText
+		m.runArgs = msg.args
+		m.viewingJob = 0
+		m.bookmarks = nil
+		m.runStart = time.Now()
 		m.statusErr = false
-		m.statusText = "running " + msg.cmdText
-		m.viewport.SetContent("$ " + msg.cmdText + "\n\nRunning...")
-		m.viewport.GotoTop()
-		return m, m.spinner.Tick
+		m.statusText = runningStatus(msg.cmdText, msg.args)
+		m.runSubcmd = subcommandName(msg.args)
+		m.listenAddr = ""
+		if m.runSubcmd == "host" {
+			m.hostedTunnel = hostArgTunnel(msg.args)
+		}
+		m.hasProgress = false
+		m.liveLines = nil
+		m.liveHeader = "$ " + msg.cmdText + "\n\n"
+		title := "running " + subcommandName(msg.args)
+		if wf := m.workflow; wf != nil {
+			m.statusText = fmt.Sprintf("workflow %s %d/%d: %s", wf.name, wf.step+1, len(wf.steps), msg.cmdText)
+			m.liveHeader = wf.output.String() + m.liveHeader
+			title = "running workflow " + wf.name
+		}
+		m = m.setOutput(m.liveHeader + "Running...")
+		m.viewport.GotoBottom()
+		m = m.logCommand("start", msg.cmdText)
+		return m, tea.Batch(m.spinnerTick(), m.titleCmd(title))
+
+	case runOutputMsg:
+		if j := m.jobFor(msg.ch); j != nil {
+			m, redraw := m.onJobOutput(j, msg.line)
+			return m, tea.Batch(redraw, waitForStream(msg.ch))
+		}
+		m.liveLines = append(m.liveLines, stampedLine{at: time.Now(), text: msg.line})
+		if frac, ok := parseProgress(msg.line); ok {
+			m.progress = frac
+			m.hasProgress = true
+		}
+		m = m.noteListenAddr(msg.line)
+		m = m.noteHostedTunnel(msg.line)
+		if m.viewingJob != 0 {
+			return m, waitForStream(msg.ch)
+		}
+		m, redraw := m.scheduleRedraw()
+		return m, tea.Batch(redraw, waitForStream(msg.ch))
+
+	case liveRedrawMsg:
+		m = m.onLiveRedraw()
 
 	case runFinishedMsg:
 		if msg.cmdText == "which devtunnel" {
 			if msg.err != nil {
 				m.devtunnelFound = false
 				m.statusErr = true
-				m.statusText = "devtunnel not found in PATH"
-			} else {
-				m.devtunnelFound = true
-				m.statusErr = false
-				m.statusText = "ready"
+				m.statusText = msg.output
+				return m, m.titleCmd("devtunnel not found")
 			}
-			return m, nil
+			m.devtunnelFound = true
+			m.statusErr = false
+			m.statusText = "ready"
+			if len(m.warnings) > 0 {
+				m.statusErr = true
+				m.statusText = fmt.Sprintf("%d config warning(s), see output", len(m.warnings))
+			}
+			next, cmd := m.onReady()
+			return next, tea.Batch(cmd, m.titleCmd("ready"))
+		}
+		if j := m.jobFor(msg.ch); j != nil {
+			return m.onJobFinished(j, msg)
+		}
+		m.runCh = nil
+		m.viewingJob = 0
+
+		m.outcomes = append(m.outcomes, msg.err == nil)
+		m.failedCmd = nil
+		if msg.err != nil {
+			m.failedCmd = msg.args
+		}
+		if len(m.outcomes) > maxOutcomes {
+			m.outcomes = m.outcomes[len(m.outcomes)-maxOutcomes:]
+		}
+		m.lastRunAt = m.runStart
+		entry := logEntry{
+			cmdText: msg.cmdText,
+			args:    msg.args,
+			start:   m.runStart,
+			end:     time.Now(),
+			output:  msg.output,
+			err:     msg.err,
+		}
+		m = m.appendSessionLog(entry)
+		m = m.logCommand("finish", fmt.Sprintf("%s [%s, %s]", msg.cmdText, entry.exitStatus(), entry.end.Sub(entry.start).Round(time.Millisecond)))
+		m.recordCache(msg)
+		if m.workflow != nil {
+			return m.advanceWorkflow(msg)
 		}
 
 		m.running = false
 		m.lastOutput = msg.output
+		m.lastStdout = msg.stdout
+		m.lastStderr = msg.stderr
+		m.haveStreams = true
+		m.outStream = streamCombined
+		m.lastText = msg.cmdText
+		m.cachedAt = time.Time{}
+		var refresh tea.Cmd
+		if f := strings.Fields(msg.cmdText); msg.err == nil && len(f) > 1 {
+			switch f[1] {
+			case "clusters":
+				if rows := parseClusters(msg.output); len(rows) > 0 {
+					m = m.setClusters(rows)
+				}
+			case "set", "unset":
+				refresh = m.fetchDefaultTunnelCmd()
+			case "user":
+				if len(f) > 2 && f[2] == "login" {
+					refresh = m.fetchLoginCmd()
+				}
+			}
+		}
+		body := m.formatOutput(msg.cmdText, msg.output)
 		if msg.err != nil {
+			status, notFound := describeFailure(m.procs, msg.args, msg.err)
 			m.statusErr = true
-			m.statusText = "command failed"
+			m.statusText = status
+			if notFound && strings.TrimSpace(msg.output) == "" {
+				body = status + "\nCheck the program name for typos, or install it and make sure it is on PATH."
+			}
+			if isAuthFailure(msg) && subcommandName(msg.args) != "user" {
+				m.login = loginNo
+				m.loginPrompt = true
+				m.statusText = "not signed in or credentials expired"
+			}
 		} else {
 			m.statusErr = false
 			m.statusText = "command completed"
+			if strings.Join(msg.args, " ") == "devtunnel user logout" {
+				m = m.afterLogout()
+			}
+		}
+		m.logoutClear = false
+		if m.timestamps && len(m.liveLines) > 0 {
+			// keep the stamped lines that were followed live
+			display, raw := m.renderStreamed("$ "+msg.cmdText+"\n\n", m.liveLines)
+			m = m.setOutput(display)
+			m.outRaw = raw
+		} else {
+			m = m.setOutput("$ " + msg.cmdText + "\n\n" + body)
 		}
-		m.viewport.SetContent("$ " + msg.cmdText + "\n\n" + msg.output)
 		m.viewport.GotoTop()
-		return m, nil
+		if base := m.rerunBase; base != nil {
+			m.rerunBase = nil
+			if base.cmdText == msg.cmdText {
+				m = m.showRerunDiff(base, msg.output)
+			}
+		}
+		title := "ready"
+		if msg.err != nil {
+			title = subcommandName(msg.args) + " failed"
+		}
+		if m.watching {
+			refresh = tea.Batch(refresh, m.watchTickCmd())
+		}
+		var hook tea.Cmd
+		m, hook = m.runHook(msg)
+		return m, tea.Batch(refresh, hook, notifyCmd(m.cfg.Notify, msg.cmdText, msg.err != nil, time.Since(m.runStart)), m.titleCmd(title))
+
+	case hookDoneMsg:
+		m = m.onHookDone(msg)
+
+	case defaultTunnelMsg:
+		m.defaultTunnel = msg.id
+
+	case prefetchTickMsg:
+		return m.onPrefetchTick()
+
+	case prefetchMsg:
+		m = m.onPrefetched(msg)
+
+	case pollTickMsg:
+		if m.pollPaused {
+			return m, m.pollTickCmd()
+		}
+		return m, tea.Batch(m.pollTunnelsCmd(), m.pollTickCmd())
+
+	case loginFinishedMsg:
+		return m.onLoginFinished(msg)
+
+	case loginStateMsg:
+		m.login = msg.state
+		m.loginUser = msg.user
+
+	case watchTickMsg:
+		return m.onWatchTick(msg)
+
+	case tunnelCountMsg:
+		m.haveCount = msg.err == nil
+		m.tunnelCount = msg.count
 
 	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		if m.minimized {
+			return m.updateMinimized(msg)
+		}
+		if m.whatsNew != "" {
+			return m.updateWhatsNew(msg)
+		}
+		if m.helpOpen {
+			return m.updateHelp(msg)
+		}
+		if m.logOpen {
+			return m.updateLog(msg)
+		}
+		if m.pickerMode {
+			return m.updatePicker(msg)
+		}
+		if m.loginPrompt {
+			return m.updateLoginPrompt(msg)
+		}
+		if m.logoutPrompt {
+			return m.updateLogoutPrompt(msg)
+		}
+		if len(m.pasteSteps) > 0 {
+			return m.updatePasteConfirm(msg)
+		}
+		if m.confirmMode {
+			return m.updateConfirm(msg)
+		}
 		if m.formMode {
 			return m.updateForm(msg)
 		}
+		if m.wizard != nil {
+			return m.updateWizard(msg)
+		}
 		if m.cmdMode {
 			return m.updateCmdMode(msg)
 		}
+		if m.flagsMode {
+			return m.updateFlagsMode(msg)
+		}
+		if m.promptMode {
+			return m.updatePrompt(msg)
+		}
+		if m.watching {
+			return m.stopWatch(), nil
+		}
+		if i, ok := favoriteKey(msg); ok {
+			return m.runFavorite(i)
+		}
 		if m.filterMode {
 			return m.updateFilterMode(msg)
 		}
 
+		if m.focusPane == 2 && !(m.state.GlobalNav && isNavKey(msg)) {
+			if msg.String() == "/" {
+				return m.openPrompt(promptSearch, "search output", m.searchQuery)
+			}
+			var handled bool
+			if m, handled = m.updateOutputKey(msg); handled {
+				return m, nil
+			}
+		}
+		if m.zoomed && isNavKey(msg) {
+			m.zoomed = false
+			m = m.layoutViewport()
+		}
+
 		switch {
+		case msg.Type == tea.KeyCtrlC && m.viewedJob() != nil && !m.viewedJob().done:
+			j := m.viewedJob()
+			j.interrupt()
+			m.statusErr = true
+			m.statusText = fmt.Sprintf("interrupting job %d", j.id)
+		case msg.Type == tea.KeyCtrlC && m.running && m.interrupt != nil:
+			m.interrupt()
+			m.statusErr = true
+			m.statusText = "interrupting (Ctrl-C again once stopped to quit)"
 		case msg.Type == tea.KeyCtrlC || msg.String() == "q":
-			return m, tea.Quit
-		case msg.Type == tea.KeyLeft || msg.String() == "h":
-			if m.catIdx > 0 {
-				m.catIdx--
-				m.cmdIdx = 0
+			return m, tea.Sequence(m.titleCmd(""), tea.Quit)
+		case msg.String() == "z":
+			m.zoomed = !m.zoomed
+			m = m.layoutViewport()
+		case msg.Type == tea.KeyTab:
+			m.focusPane = m.nextPane(1)
+		case msg.Type == tea.KeyShiftTab:
+			m.focusPane = m.nextPane(-1)
+		case msg.String() == "x":
+			m.state.SwapPanes = !m.state.SwapPanes
+			if err := saveState(m.state); err != nil {
+				m.statusErr = true
+				m.statusText = "could not save layout: " + err.Error()
 			}
-		case msg.Type == tea.KeyRight || msg.String() == "l":
-			if m.catIdx < len(m.categories)-1 {
-				m.catIdx++
-				m.cmdIdx = 0
+		case msg.String() == "ctrl+g":
+			m.state.GlobalNav = !m.state.GlobalNav
+			m.statusErr = false
+			m.statusText = "navigation keys follow the focused pane"
+			if m.state.GlobalNav {
+				m.statusText = "navigation keys always move the command list"
+			}
+			if err := saveState(m.state); err != nil {
+				m.statusErr = true
+				m.statusText = "could not save layout: " + err.Error()
+			}
+		case msg.String() == "H":
+			m.state.HideCategories = !m.state.HideCategories
+			if m.state.HideCategories && m.focusPane == 0 {
+				m.focusPane = 1
 			}
+			m = m.layoutViewport()
+			if err := saveState(m.state); err != nil {
+				m.statusErr = true
+				m.statusText = "could not save layout: " + err.Error()
+			}
+		case msg.String() == "A":
+			m.state.ShowCommand = !m.state.ShowCommand
+			if err := saveState(m.state); err != nil {
+				m.statusErr = true
+				m.statusText = "could not save layout: " + err.Error()
+			}
+		case msg.Type == tea.KeyLeft || msg.String() == "h":
+			m = m.stepCategory(-1)
+		case msg.Type == tea.KeyRight || msg.String() == "l":
+			m = m.stepCategory(1)
 		case msg.String() == ":":
 			m.cmdMode = true
 			m.cmdInput.SetValue("")
@@ -304,8 +886,124 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, textinput.Blink
 		case msg.String() == "r":
 			if len(m.lastCmd) > 0 {
-				return m, runCommandCmd(m.lastCmd)
+				if next, asked := m.confirmForced(m.lastCmd, "previous command"); asked {
+					return next, nil
+				}
+				return m.runCached(m.lastCmd)
+			}
+		case msg.String() == "D":
+			return m.rerunWithDiff()
+		case msg.String() == "F":
+			if len(m.lastCmd) > 0 {
+				return m.rerun(m.lastCmd)
+			}
+		case msg.String() == "R":
+			return m.retryFailed()
+		case msg.String() == "f":
+			return m.openFlagsInput()
+		case msg.String() == "P":
+			if m.cfg.PollSeconds <= 0 {
+				m.statusErr = true
+				m.statusText = "polling is off (set pollSeconds in config)"
+				return m, nil
 			}
+			m.pollPaused = !m.pollPaused
+		case msg.String() == "c":
+			m.compactCommands = !m.compactCommands
+		case msg.String() == "I":
+			m.showIndices = !m.showIndices
+		case msg.String() == "S":
+			return m.toggleSafeMode(), nil
+		case msg.String() == "Y":
+			return m.copyMarkdown(), nil
+		case msg.String() == "U":
+			return m.copyCurlCommand(), nil
+		case msg.String() == "&":
+			if m.running {
+				return m.detachForeground(), nil
+			}
+			return m.runInBackground()
+		case msg.String() == "J":
+			return m.openJobsPicker(), nil
+		case msg.String() == "?":
+			m.helpOpen = true
+		case msg.String() == "L":
+			m = m.openLog()
+		case msg.String() == "X":
+			m = m.openRawExport()
+		case msg.String() == "e":
+			m.wrapFooter = !m.wrapFooter
+		case msg.String() == "C":
+			m = m.copyConnectCommand()
+		case msg.String() == "ctrl+p":
+			m = m.openCategoryPicker()
+		case msg.String() == "a":
+			m.showAdvanced = !m.showAdvanced
+			m.cmdIdx = min(m.cmdIdx, max(0, len(m.visibleCommands())-1))
+		case msg.String() == "i":
+			m.showInspector = !m.showInspector
+		case msg.String() == "K":
+			if len(m.clusters) == 0 {
+				m.clusterPickPending = true
+				m.statusErr = false
+				m.statusText = "loading clusters"
+				return m, m.fetchClustersCmd()
+			}
+			return m.openClusterPicker(), nil
+		case msg.String() == "b":
+			if m.lastText == "" {
+				m.statusErr = true
+				m.statusText = "run a command before setting a baseline"
+				return m, nil
+			}
+			m.baseline = m.lastOutput
+			m.baselineCmd = m.lastText
+			m.statusErr = false
+			m.statusText = "baseline set: " + m.lastText
+		case msg.String() == "B":
+			return m.showBaselineDiff(), nil
+		case msg.String() == "m":
+			m = m.togglePin()
+		case msg.String() == "w":
+			return m.toggleWatch()
+		case msg.String() == "s":
+			m = m.cycleOutputStream()
+		case msg.String() == "*":
+			m = m.toggleFavorite()
+		case msg.String() == "T":
+			m.timestamps = !m.timestamps
+			m.statusErr = false
+			m.statusText = "timestamps off"
+			if m.timestamps {
+				m.statusText = "timestamps on"
+			}
+			if m.running {
+				m = m.showLive()
+			}
+		case msg.String() == "t":
+			m = m.toggleHumanTimes()
+		case msg.String() == "O":
+			m = m.cycleOrder()
+		case msg.String() == "M":
+			return m.openBookmarkPrompt()
+		case msg.String() == "'":
+			m = m.openBookmarkPicker()
+		case msg.String() == "W":
+			return m.openPrompt(promptWorkDir, "working dir", m.workDirLabel())
+		case msg.String() == "E":
+			if len(m.sessionLog) == 0 {
+				m.statusErr = true
+				m.statusText = "session log is empty"
+				return m, nil
+			}
+			path, err := exportSessionLog(m.sessionLog)
+			if err != nil {
+				m.statusErr = true
+				m.statusText = "export failed: " + err.Error()
+				return m, nil
+			}
+			m.statusErr = false
+			m.statusText = "session log written to " + path
 		case msg.String() == "g":
 			m.cmdIdx = 0
 		case msg.String() == "G":
@@ -313,7 +1011,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(cmds) > 0 {
 				m.cmdIdx = len(cmds) - 1
 			}
-		case msg.String() == "1", msg.String() == "2", msg.String() == "3", msg.String() == "4", msg.String() == "5", msg.String() == "6":
+		case len(msg.String()) == 1 && msg.String()[0] >= '1' && msg.String()[0] <= '9':
 			i := int(msg.String()[0] - '1')
 			if i >= 0 && i < len(m.categories) {
 				m.catIdx = i
@@ -321,9 +1019,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.focusPane = 1
 			}
 		case msg.Type == tea.KeyUp || msg.String() == "k":
-			m.moveUp()
+			return m.moveUp().autoOpenForm()
 		case msg.Type == tea.KeyDown || msg.String() == "j":
-			m.moveDown()
+			return m.moveDown().autoOpenForm()
+		case msg.String() == "#":
+			m = m.togglePaged()
+		case msg.String() == "-":
+			return m.toggleMinimized()
+		case m.paged && (msg.String() == "p" || msg.Type == tea.KeyPgUp):
+			m = m.turnPage(-1)
+		case m.paged && (msg.String() == "n" || msg.Type == tea.KeyPgDown):
+			m = m.turnPage(1)
 		case msg.Type == tea.KeyPgUp:
 			m.viewport.HalfViewUp()
 		case msg.Type == tea.KeyPgDown:
@@ -340,53 +1046,339 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) moveUp() {
+// layoutViewport sizes the output viewport for the current terminal size,
+// giving it the full width when zoomed. The scroll position is kept by
+// percentage, so output scrolled to the end stays at the end.
+func (m model) layoutViewport() model {
+	oldMax := m.viewport.TotalLineCount() - m.viewport.Height
+	pct := 0.0
+	if oldMax > 0 {
+		pct = math.Min(1, float64(m.viewport.YOffset)/float64(oldMax))
+	}
+	m.viewport.Width = max(20, m.width-64)
+	if m.state.HideCategories {
+		m.viewport.Width += max(20, m.width/5)
+	}
+	if m.zoomed {
+		m.viewport.Width = max(20, m.width-4)
+	}
+	m.viewport.Height = max(3, max(8, m.height-10)-m.pinnedHeight())
+	newMax := max(0, m.viewport.TotalLineCount()-m.viewport.Height)
+	m.viewport.SetYOffset(int(math.Round(pct * float64(newMax))))
+	if m.outCursor < m.viewport.YOffset || m.outCursor >= m.viewport.YOffset+m.viewport.Height {
+		m.outCursor = m.viewport.YOffset
+	}
+	return m
+}
+
+// isNavKey reports whether k moves the category or command selection.
+func isNavKey(k tea.KeyMsg) bool {
+	switch k.Type {
+	case tea.KeyLeft, tea.KeyRight, tea.KeyUp, tea.KeyDown:
+		return true
+	}
+	switch k.String() {
+	case "h", "j", "k", "l", "g", "G", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		return true
+	}
+	return false
+}
+
+// onReady runs once the devtunnel binary has been found: it loads the
+// default tunnel and starts the configured startup command, if any.
+func (m model) onReady() (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{m.fetchDefaultTunnelCmd(), m.fetchLoginCmd()}
+	if m.cfg.PollSeconds > 0 {
+		cmds = append(cmds, m.pollTunnelsCmd(), m.pollTickCmd())
+	}
+	cmds = append(cmds, m.prefetchTickCmd())
+	if m.cfg.DiscoverCommands {
+		cmds = append(cmds, m.discoverCommandsCmd())
+	}
+
+	raw := strings.TrimSpace(m.cfg.StartupCommand)
+	fields := splitArgs(raw)
+	if len(fields) > 0 && fields[0] == "devtunnel" {
+		fields = fields[1:]
+	}
+	switch {
+	case raw == "":
+	case strings.HasPrefix(raw, "!") || len(fields) == 0:
+		m.statusErr = true
+		m.statusText = "startupCommand ignored: expected a devtunnel subcommand"
+	default:
+		parts := append([]string{"devtunnel"}, fields...)
+		if next, asked := m.confirmForced(parts, "startupCommand"); asked {
+			m = next
+			break
+		}
+		m.lastCmd = parts
+		cmds = append(cmds, m.runCommandCmd(parts))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) welcomeText() string {
+	if len(m.warnings) == 0 {
+		return "Output will appear here"
+	}
+	return "Config warnings:\n  " + strings.Join(m.warnings, "\n  ")
+}
+
+// formatOutput prepares command output for the viewport, highlighting JSON
+// when it parses and leaving everything else untouched.
+func (m model) formatOutput(cmdText, output string) string {
+	if m.humanTimes {
+		output = humanizeTimes(output, time.Now())
+	}
+	if looksLikeJSON(cmdText, output) {
+		if out, ok := m.styles.highlightJSON(output); ok {
+			return out
+		}
+	}
+	if f := strings.Fields(cmdText); len(f) > 1 {
+		switch f[1] {
+		case "list":
+			return m.highlightDefault(output)
+		case "limits":
+			return m.styles.renderLimits(output)
+		case "clusters":
+			return m.renderClusters(output)
+		case "ping":
+			return m.styles.withPingStats(output)
+		}
+	}
+	return output
+}
+
+// showBaselineDiff renders the latest output as a line diff against the
+// pinned baseline.
+func (m model) showBaselineDiff() model {
+	if m.baselineCmd == "" {
+		m.statusErr = true
+		m.statusText = "no baseline set (press b)"
+		return m
+	}
+	body, added, removed := m.styles.renderDiff(diffLines(splitLines(m.baseline), splitLines(m.lastOutput)))
+	header := fmt.Sprintf("diff  --- %s (baseline)\n      +++ %s (latest)\n\n", m.baselineCmd, m.lastText)
+	m = m.setOutput(header + body)
+	m.viewport.GotoTop()
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("diff: +%d -%d", added, removed)
+	return m
+}
+
+// retryFailed re-runs the latest command only if it failed, e.g. after
+// signing in or fixing the network.
+func (m model) retryFailed() (tea.Model, tea.Cmd) {
+	if m.running {
+		m.statusErr = true
+		m.statusText = "a command is still running"
+		return m, nil
+	}
+	if len(m.failedCmd) == 0 {
+		m.statusErr = false
+		m.statusText = "last command succeeded, nothing to retry"
+		if len(m.outcomes) == 0 {
+			m.statusText = "no command has run yet"
+		}
+		return m, nil
+	}
+	m.lastCmd = m.withoutForceFlag(m.failedCmd)
+	return m.rerun(m.failedCmd)
+}
+
+// openCategoryPicker lists every category for type-to-filter selection, for
+// when there are more than the number keys reach.
+func (m model) openCategoryPicker() model {
+	if len(m.categories) == 0 {
+		m.statusErr = true
+		m.statusText = "no categories"
+		return m
+	}
+	items := make([]string, len(m.categories))
+	for i, c := range m.categories {
+		items[i] = fmt.Sprintf("%s (%d)", c.name, len(m.commandsIn(i)))
+	}
+	return m.openFuzzyPicker(pickCategory, "Jump to category", items, m.catIdx)
+}
+
+// stepCategory moves the category selection by delta, stopping at the
+// ends. While a filter is active it skips categories with no matches and
+// wraps around instead.
+func (m model) stepCategory(delta int) model {
+	n := len(m.categories)
+	if strings.TrimSpace(m.filterInput.Value()) == "" {
+		if next := m.catIdx + delta; next >= 0 && next < n {
+			m.catIdx = next
+			m.cmdIdx = 0
+		}
+		return m
+	}
+	for step := 1; step < n; step++ {
+		next := ((m.catIdx+delta*step)%n + n) % n
+		if len(m.commandsIn(next)) > 0 {
+			m.catIdx = next
+			m.cmdIdx = 0
+			return m
+		}
+	}
+	m.statusErr = false
+	m.statusText = "no other category matches the filter"
+	return m
+}
+
+func (m model) moveUp() model {
 	if m.cmdIdx > 0 {
 		m.cmdIdx--
 	}
+	return m
 }
 
-func (m model) moveDown() {
+func (m model) moveDown() model {
 	cmds := m.visibleCommands()
 	if m.cmdIdx < len(cmds)-1 {
 		m.cmdIdx++
 	}
+	return m
 }
 
-func (m model) visibleCommands() []commandItem {
-	if m.catIdx < 0 || m.catIdx >= len(m.categories) {
-		return nil
+// updateOutputKey handles scrolling while the output pane has focus: j/k
+// by line, gg/G to the ends and N% to a position. A count before j/k moves
+// that many lines and before G jumps to that line, as in vim. Keys it does
+// not own report false and fall through to the normal bindings.
+func (m model) updateOutputKey(k tea.KeyMsg) (model, bool) {
+	key := k.String()
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		m.count += key
+		return m, true
 	}
-	items := m.categories[m.catIdx].commands
-	flt := strings.TrimSpace(strings.ToLower(m.filterInput.Value()))
-	if flt == "" {
-		return items
-	}
-	out := make([]commandItem, 0, len(items))
-	for _, item := range items {
-		hay := strings.ToLower(item.name + " " + item.description + " " + strings.Join(item.baseArgs, " "))
-		if strings.Contains(hay, flt) {
-			out = append(out, item)
+	count := m.count
+	m.count = ""
+	pendingG := m.pendingG
+	m.pendingG = false
+
+	switch {
+	case key == "%" && count != "":
+		pct, _ := strconv.Atoi(count)
+		maxOffset := max(0, m.viewport.TotalLineCount()-m.viewport.Height)
+		m.viewport.SetYOffset(maxOffset * min(pct, 100) / 100)
+		m.outCursor = m.viewport.YOffset
+	case key == "g":
+		if pendingG {
+			m.viewport.GotoTop()
+			m.outCursor = 0
+		} else {
+			m.pendingG = true
 		}
+	case key == "G" && count != "":
+		n, _ := strconv.Atoi(count)
+		m = m.setCursor(n - 1)
+	case key == "G":
+		m.viewport.GotoBottom()
+		m.outCursor = len(m.outLines) - 1
+	case key == "j" || k.Type == tea.KeyDown:
+		m = m.moveCursor(countOr1(count))
+	case key == "k" || k.Type == tea.KeyUp:
+		m = m.moveCursor(-countOr1(count))
+	case key == "=":
+		m.state.RelativeNumbers = !m.state.RelativeNumbers
+		if err := saveState(m.state); err != nil {
+			m.statusErr = true
+			m.statusText = "could not save layout: " + err.Error()
+		}
+	case key == "n" && m.searchQuery != "":
+		m = m.stepMatch(1)
+	case key == "N" && m.searchQuery != "":
+		m = m.stepMatch(-1)
+	case key == "]":
+		m = m.stepLink(1)
+	case key == "[":
+		m = m.stepLink(-1)
+	case key == "enter" || key == "o":
+		var opened bool
+		if m, opened = m.openSelectedLink(); !opened {
+			if key == "o" {
+				m.statusErr = true
+				m.statusText = "no link selected (] selects the next one)"
+				return m, true
+			}
+			return m, false
+		}
+	case key == ">":
+		m = m.captureToken()
+	case key == "v":
+		m = m.toggleVisual()
+	case key == "esc" && m.visual:
+		m.visual = false
+	case key == "y" && m.visual:
+		m = m.copyVisual()
+	case key == "y":
+		line := m.cursorText()
+		copyToClipboard(line)
+		m.statusErr = false
+		m.statusText = "copied line " + strconv.Itoa(m.outCursor+1)
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// visibleCommands returns the filtered commands of the current category,
+// or of every category when the scope toggle is on.
+func (m model) visibleCommands() []commandItem {
+	if !m.filterAll {
+		return m.commandsIn(m.catIdx)
+	}
+	var out []commandItem
+	for i := range m.categories {
+		out = append(out, m.commandsIn(i)...)
 	}
 	return out
 }
 
-func (m model) runSelected() (tea.Model, tea.Cmd) {
-	if !m.devtunnelFound {
-		m.statusErr = true
-		m.statusText = "install devtunnel CLI first"
-		return m, nil
+// commandsIn returns the commands of category i that pass every active
+// filter.
+func (m model) commandsIn(i int) []commandItem {
+	if i < 0 || i >= len(m.categories) {
+		return nil
 	}
+	items := sortCommands(m.categories[i].commands, m.state.CommandOrder[m.categories[i].name])
+	return applyFilters(items, m.commandFilters())
+}
+
+func (m model) runSelected() (tea.Model, tea.Cmd) {
 	cmds := m.visibleCommands()
 	if len(cmds) == 0 {
+		m.statusErr = true
+		m.statusText = m.emptyCommandsText()
 		return m, nil
 	}
 	if m.cmdIdx >= len(cmds) {
 		m.cmdIdx = len(cmds) - 1
 	}
-	cmd := cmds[m.cmdIdx]
+	return m.runItem(cmds[m.cmdIdx])
+}
 
+// runItem runs cmd directly, or opens its form when it takes input.
+func (m model) runItem(cmd commandItem) (tea.Model, tea.Cmd) {
+	if !m.devtunnelFound {
+		m.statusErr = true
+		m.statusText = "install devtunnel CLI first"
+		return m, nil
+	}
+	if m.safeMode && cmd.destructive {
+		m.statusErr = true
+		m.statusText = "safe mode: " + cmd.name + " is locked"
+		return m, nil
+	}
+	if isLogout(cmd) {
+		m.logoutPrompt = true
+		return m, nil
+	}
+	if len(cmd.wizard) > 0 {
+		return m.openWizard(cmd)
+	}
 	if cmd.name == ": command mode" {
 		m.cmdMode = true
 		m.cmdInput.SetValue("")
@@ -395,14 +1387,26 @@ func (m model) runSelected() (tea.Model, tea.Cmd) {
 	}
 
 	labels := append([]string{}, cmd.required...)
+	for _, mf := range cmd.multi {
+		labels = append(labels, mf.label)
+	}
 	if cmd.optional != "" {
 		labels = append(labels, cmd.optional)
 	}
 
 	if len(labels) == 0 {
-		parts := append([]string{"devtunnel"}, cmd.baseArgs...)
+		if cmd.workflow != nil {
+			return m.startWorkflow(cmd.workflow, nil)
+		}
+		if next, asked := m.withForceFlag(m.withClusterArgs(&cmd, commandArgs(&cmd))); asked {
+			return next, nil
+		}
+		if next, asked := m.confirmNoArgs(&cmd); asked {
+			return next, nil
+		}
+		parts := m.withCluster(&cmd, append([]string{"devtunnel"}, cmd.baseArgs...))
 		m.lastCmd = parts
-		return m, runCommandCmd(parts)
+		return m.runCached(parts)
 	}
 
 	m.formMode = true
@@ -410,37 +1414,89 @@ func (m model) runSelected() (tea.Model, tea.Cmd) {
 	m.formTitle = cmd.name
 	m.formLabels = labels
 	m.formInputs = make([]textinput.Model, len(labels))
+	m.formChecks = make([]checklist, len(labels))
 	m.formIndex = 0
+	for j := range cmd.multi {
+		mf := &m.formCmd.multi[j]
+		m.formChecks[len(cmd.required)+j] = newChecklist(mf)
+	}
 
 	for i, label := range labels {
 		ti := textinput.New()
 		ti.Prompt = "> "
 		ti.Placeholder = label
-		ti.Width = 60
+		if i < len(cmd.placeholders) && cmd.placeholders[i] != "" {
+			ti.Placeholder = cmd.placeholders[i]
+		}
 		ti.CharLimit = 300
+		if cmd.isSensitive(label) {
+			ti.EchoMode = textinput.EchoPassword
+		}
 		m.formInputs[i] = ti
 	}
 	m.formInputs[0].Focus()
+	m = m.resizeInputs()
 
 	return m, textinput.Blink
 }
 
+// focusField moves the form to field i, clamped to the field range. Values
+// already entered are kept.
+func (m model) focusField(i int) model {
+	i = max(0, min(i, len(m.formInputs)-1))
+	m.formInputs[m.formIndex].Blur()
+	m.formIndex = i
+	m.formInputs[m.formIndex].Focus()
+	return m
+}
+
 func (m model) updateForm(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if next, cmd, left := m.leaveAutoForm(k); left {
+		return next, cmd
+	}
+	if c := m.formChecks[m.formIndex]; c.field != nil {
+		var handled bool
+		if m.formChecks[m.formIndex], handled = c.update(k); handled {
+			return m, nil
+		}
+		if k.String() == "enter" && len(c.selected()) == 0 {
+			m.statusErr = true
+			m.statusText = "select at least one " + c.field.label
+			return m, nil
+		}
+	}
+
 	switch k.String() {
 	case "esc":
-		m.formMode = false
-		m.formCmd = nil
-		m.formInputs = nil
-		m.formLabels = nil
-		m.formTitle = ""
-		m.formIndex = 0
+		m.nextBackground = false
+		return m.closeForm(), nil
+	case "shift+tab", "up":
+		return m.focusField(m.formIndex - 1), nil
+	case "tab", "down":
+		return m.focusField(m.formIndex + 1), nil
+	case "ctrl+r":
+		return m.focusField(0), nil
+	case "ctrl+o":
+		if c := m.formChecks[m.formIndex]; c.field == nil && !m.insertCaptured(&m.formInputs[m.formIndex]) {
+			m.statusErr = true
+			m.statusText = "nothing captured (> on an output line captures its token)"
+		}
+		return m, nil
+	case "ctrl+y":
+		if m.formCmd == nil {
+			return m, nil
+		}
+		snippet := m.formSnippet()
+		copyToClipboard(snippet)
+		m.statusErr = false
+		m.statusText = "copied shell command (not run)"
+		if _, _, missing := m.formArgs(); missing != "" {
+			m.statusText += "; note: " + missing + " is still empty"
+		}
 		return m, nil
 	case "enter":
 		if m.formIndex < len(m.formInputs)-1 {
-			m.formInputs[m.formIndex].Blur()
-			m.formIndex++
-			m.formInputs[m.formIndex].Focus()
-			return m, nil
+			return m.focusField(m.formIndex + 1), nil
 		}
 
 		if m.formCmd == nil {
@@ -448,38 +1504,39 @@ func (m model) updateForm(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		parts := append([]string{"devtunnel"}, m.formCmd.baseArgs...)
-		reqCount := len(m.formCmd.required)
-		for i := 0; i < reqCount; i++ {
-			v := strings.TrimSpace(m.formInputs[i].Value())
-			if v == "" {
-				m.statusErr = true
-				m.statusText = "missing required: " + m.formCmd.required[i]
-				m.formMode = false
-				m.formCmd = nil
-				return m, nil
+		args, params, missing := m.formArgList()
+		for i, label := range m.formLabels {
+			if v := strings.TrimSpace(m.formInputs[i].Value()); v != "" && m.formCmd.isSensitive(label) {
+				m.secrets[v] = true
 			}
-			parts = append(parts, v)
 		}
-
-		if m.formCmd.optional != "" {
-			i := len(m.formInputs) - 1
-			if i >= 0 {
-				extra := strings.TrimSpace(m.formInputs[i].Value())
-				if extra != "" {
-					parts = append(parts, strings.Fields(extra)...)
+		if missing != "" {
+			m.statusErr = true
+			m.statusText = "missing required: " + missing
+			for i, label := range m.formLabels {
+				if label == missing {
+					return m.focusField(i), nil
 				}
 			}
+			return m, nil
+		}
+		for i, label := range m.formLabels {
+			check := m.formCmd.validate[label]
+			if check == nil {
+				continue
+			}
+			if err := check(strings.TrimSpace(m.formInputs[i].Value())); err != nil {
+				m.statusErr = true
+				m.statusText = "invalid " + label + ": " + err.Error()
+				return m.focusField(i), nil
+			}
 		}
 
-		m.formMode = false
-		m.formCmd = nil
-		m.formInputs = nil
-		m.formLabels = nil
-		m.formTitle = ""
-		m.formIndex = 0
-		m.lastCmd = parts
-		return m, runCommandCmd(parts)
+		if wf := m.formCmd.workflow; wf != nil {
+			return m.closeForm().startWorkflow(wf, params)
+		}
+
+		return m.closeForm().runOrConfirm(args)
 	}
 
 	var cmd tea.Cmd
@@ -487,12 +1544,99 @@ func (m model) updateForm(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// formArgs assembles the argv for the current form values. missing names
+// the first empty required field, if any.
+func (m model) formArgs() (parts []string, params map[string]string, missing string) {
+	args, params, missing := m.formArgList()
+	return argValues(args), params, missing
+}
+
+// formArgList builds the form's argv, recording which field each argument
+// came from.
+func (m model) formArgList() (args []sourcedArg, params map[string]string, missing string) {
+	args = commandArgs(m.formCmd)
+	params = map[string]string{}
+	for i, name := range m.formCmd.required {
+		v := strings.TrimSpace(m.formInputs[i].Value())
+		if v == "" && missing == "" {
+			missing = name
+		}
+		params[name] = v
+		if i >= len(m.formCmd.params) {
+			args = append(args, sourcedArg{value: v, source: "required: " + name})
+		}
+	}
+	for i, a := range args {
+		if filled := fillPlaceholders(a.value, params); filled != a.value {
+			args[i] = sourcedArg{value: filled, source: "template: " + a.value}
+		}
+	}
+
+	for j, mf := range m.formCmd.multi {
+		picked := m.formChecks[len(m.formCmd.required)+j].selected()
+		if len(picked) == 0 && missing == "" {
+			missing = mf.label
+		}
+		for _, opt := range picked {
+			if mf.flag != "" {
+				args = append(args, sourcedArg{value: mf.flag, source: mf.label})
+			}
+			args = append(args, sourcedArg{value: opt, source: mf.label})
+		}
+	}
+
+	if m.formCmd.optional != "" {
+		i := len(m.formInputs) - 1
+		if i >= 0 {
+			for _, f := range splitArgs(m.formInputs[i].Value()) {
+				args = append(args, sourcedArg{value: f, source: "optional: " + m.formCmd.optional})
+			}
+		}
+	}
+	return m.withClusterArgs(m.formCmd, args), params, missing
+}
+
+func (m model) closeForm() model {
+	m.formMode = false
+	m.formCmd = nil
+	m.formInputs = nil
+	m.formChecks = nil
+	m.formLabels = nil
+	m.formTitle = ""
+	m.formIndex = 0
+	m.formAuto = false
+	return m
+}
+
 func (m model) updateCmdMode(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if next, ok := m.handlePaste(k); ok {
+		return next, nil
+	}
+	if next, ok := m.insertFlagSnippet(k.String()); ok {
+		return next, nil
+	}
+	if k.String() == "ctrl+o" {
+		if !m.insertCaptured(&m.cmdInput) {
+			m.statusErr = true
+			m.statusText = "nothing captured (> on an output line captures its token)"
+		}
+		return m, nil
+	}
 	switch k.String() {
 	case "esc":
 		m.cmdMode = false
 		m.cmdInput.Blur()
 		return m, nil
+	case "ctrl+s":
+		raw := strings.TrimSpace(m.cmdInput.Value())
+		if raw == "" || strings.HasPrefix(raw, "!") {
+			m.statusErr = true
+			m.statusText = "type a devtunnel command to save as a favorite"
+			return m, nil
+		}
+		m.cmdMode = false
+		m.cmdInput.Blur()
+		return m.addFavorite(templateName(raw)), nil
 	case "enter":
 		raw := strings.TrimSpace(m.cmdInput.Value())
 		m.cmdMode = false
@@ -500,15 +1644,83 @@ func (m model) updateCmdMode(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if raw == "" {
 			return m, nil
 		}
-		parts := append([]string{"devtunnel"}, strings.Fields(raw)...)
+		if shell, ok := strings.CutPrefix(raw, "!"); ok {
+			if !m.cfg.AllowShell {
+				m.statusErr = true
+				m.statusText = "shell commands are disabled (set allowShell in config)"
+				return m, nil
+			}
+			parts := []string{"sh", "-c", strings.TrimSpace(shell)}
+			m.lastCmd = parts
+			return m, m.runCommandCmd(parts)
+		}
+		parts := append([]string{"devtunnel"}, m.expandAlias(splitArgs(raw))...)
+		if next, asked := m.confirmForced(parts, "typed"); asked {
+			return next, nil
+		}
 		m.lastCmd = parts
-		return m, runCommandCmd(parts)
+		return m, m.runCommandCmd(parts)
 	}
 	var cmd tea.Cmd
 	m.cmdInput, cmd = m.cmdInput.Update(k)
 	return m, cmd
 }
 
+// openFlagsInput starts inline flag entry for the selected command when its
+// only input is the optional flags field.
+func (m model) openFlagsInput() (tea.Model, tea.Cmd) {
+	cmds := m.visibleCommands()
+	if len(cmds) == 0 {
+		return m, nil
+	}
+	cmd := cmds[min(m.cmdIdx, len(cmds)-1)]
+	if len(cmd.required) > 0 || cmd.optional == "" || cmd.workflow != nil {
+		m.statusErr = true
+		m.statusText = "inline flags need a command without required fields (use Enter)"
+		return m, nil
+	}
+	m.flagsMode = true
+	m.flagsCmd = &cmd
+	m.flagsInput.Prompt = strings.Join(append([]string{"devtunnel"}, cmd.baseArgs...), " ") + " "
+	m.flagsInput.Placeholder = cmd.optional
+	m.flagsInput.SetValue("")
+	m.flagsInput.Focus()
+	m = m.resizeInputs()
+	return m, textinput.Blink
+}
+
+func (m model) updateFlagsMode(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "esc":
+		m.flagsMode = false
+		m.flagsCmd = nil
+		m.flagsInput.Blur()
+		return m, nil
+	case "enter":
+		cmd := m.flagsCmd
+		m.flagsMode = false
+		m.flagsCmd = nil
+		m.flagsInput.Blur()
+		if !m.devtunnelFound {
+			m.statusErr = true
+			m.statusText = "install devtunnel CLI first"
+			return m, nil
+		}
+		args := commandArgs(cmd)
+		for _, f := range splitArgs(m.flagsInput.Value()) {
+			args = append(args, sourcedArg{value: f, source: "inline flags"})
+		}
+		return m.runOrConfirm(m.withClusterArgs(cmd, args))
+	}
+	var cmd tea.Cmd
+	m.flagsInput, cmd = m.flagsInput.Update(k)
+	return m, cmd
+}
+
+func (m model) shellInput() bool {
+	return strings.HasPrefix(strings.TrimSpace(m.cmdInput.Value()), "!")
+}
+
 func (m model) updateFilterMode(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch k.String() {
 	case "esc":
@@ -520,34 +1732,68 @@ func (m model) updateFilterMode(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterInput.Blur()
 		m.cmdIdx = 0
 		return m, nil
+	case "ctrl+r":
+		m.filterRegex = !m.filterRegex
+		m.filterInput.Prompt = "/ "
+		if m.filterRegex {
+			m.filterInput.Prompt = "re/ "
+		}
+		m = m.resizeInputs()
+		m.filterRe, m.filterErr = compileFilter(m.filterRegex, m.filterInput.Value())
+		m.cmdIdx = 0
+		return m, nil
+	case "ctrl+a":
+		m.filterAll = !m.filterAll
+		m.cmdIdx = 0
+		return m, nil
+	case "ctrl+t":
+		return m.cycleFilterTag(), nil
 	}
 	var cmd tea.Cmd
 	m.filterInput, cmd = m.filterInput.Update(k)
+	m.filterRe, m.filterErr = compileFilter(m.filterRegex, m.filterInput.Value())
 	m.cmdIdx = 0
 	return m, cmd
 }
 
-func runCommandCmd(parts []string) tea.Cmd {
+// compileFilter compiles a case-insensitive filter regex once per edit so
+// visibleCommands does not recompile on every render.
+func compileFilter(enabled bool, pattern string) (*regexp.Regexp, string) {
+	pattern = strings.TrimSpace(pattern)
+	if !enabled || pattern == "" {
+		return nil, ""
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, "invalid regex, filter ignored"
+	}
+	return re, ""
+}
+
+func (m model) runCommandCmd(parts []string) tea.Cmd {
 	if len(parts) == 0 {
 		return nil
 	}
-	cmdText := strings.Join(parts, " ")
+	cmdText := m.secrets.displayCmd(parts)
+	if m.safeMode && refusedInSafeMode(parts) {
+		return safeModeRefusal(cmdText)
+	}
+	if m.promptHandlingFor(parts).interactive {
+		return m.runInteractiveCmd(parts, cmdText)
+	}
+	procs := m.procs
+	dir := m.workDir
+	timeout := time.Duration(m.cfg.TimeoutMinutes) * time.Minute
+	ctx, interrupt := context.WithCancel(context.Background())
+	ch := make(chan tea.Msg, 64)
 	return tea.Sequence(
-		func() tea.Msg { return runStartedMsg{cmdText: cmdText} },
+		func() tea.Msg { return runStartedMsg{cmdText: cmdText, args: parts, interrupt: interrupt, ch: ch} },
 		func() tea.Msg {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-			defer cancel()
-
-			cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
-			var out bytes.Buffer
-			cmd.Stdout = &out
-			cmd.Stderr = &out
-			err := cmd.Run()
-
-			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-				return runFinishedMsg{cmdText: cmdText, output: out.String() + "\n\nTimed out after 10 minutes.", err: ctx.Err()}
-			}
-			return runFinishedMsg{cmdText: cmdText, output: out.String(), err: err}
+			go func() {
+				defer interrupt()
+				streamCommand(ctx, procs, parts, dir, cmdText, timeout, ch)
+			}()
+			return <-ch
 		},
 	)
 }
@@ -556,6 +1802,9 @@ func (m model) View() string {
 	if !m.ready {
 		return "Loading..."
 	}
+	if m.minimized {
+		return m.renderMinimized()
+	}
 
 	header := m.renderHeader()
 	main := m.renderMain()
@@ -567,14 +1816,26 @@ func (m model) View() string {
 func (m model) renderHeader() string {
 	left := m.styles.header.Render(" DevTunnels TUI ")
 	mode := "NORMAL"
-	if m.filterMode {
+	if m.helpOpen {
+		mode = "HELP"
+	} else if m.logOpen {
+		mode = "LOG"
+	} else if m.pickerMode {
+		mode = "PICK"
+	} else if m.confirmMode || m.logoutPrompt || len(m.pasteSteps) > 0 {
+		mode = "CONFIRM"
+	} else if m.filterMode {
 		mode = "FILTER"
-	} else if m.cmdMode {
+	} else if m.cmdMode || m.flagsMode || m.promptMode {
 		mode = "COMMAND"
-	} else if m.formMode {
+	} else if m.formMode || m.wizard != nil {
 		mode = "FORM"
+	} else if m.watching {
+		mode = fmt.Sprintf("WATCH #%d every %s", m.watchIter, m.watchEvery)
 	} else if m.running {
 		mode = "RUNNING"
+	} else if m.visual && m.focusPane == 2 {
+		mode = "VISUAL"
 	}
 
 	statusStyle := m.styles.ok
@@ -586,10 +1847,46 @@ func (m model) renderHeader() string {
 	}
 
 	statusText := m.statusText
-	if m.running {
-		statusText = m.spinner.View() + " " + statusText
+	if m.running && m.hasProgress {
+		statusText = m.styles.renderProgress(m.progress, 20) + " " + statusText
+	} else if m.running {
+		statusText = m.spinnerView() + " " + statusText
+	}
+	info := "mode:" + mode
+	if m.cluster != "" {
+		info += "  cluster:" + m.cluster
+	}
+	if m.devtunnelFound {
+		info += "  default:" + m.styles.defaultMark.Render(valueOr(m.defaultTunnel, "none"))
+	}
+	switch m.login {
+	case loginYes:
+		info += "  user:" + m.loginUser
+	case loginNo:
+		info += "  " + m.styles.warn.Render("not logged in")
 	}
-	right := m.styles.headerInfo.Render(fmt.Sprintf("mode:%s  %s", mode, statusStyle.Render(statusText)))
+	if m.haveCount {
+		info += fmt.Sprintf("  tunnels:%d", m.tunnelCount)
+	}
+	if n := m.runningJobs(); n > 0 {
+		info += fmt.Sprintf("  jobs:%d", n)
+	}
+	if m.safeMode {
+		info += "  " + m.styles.warn.Render("safe mode")
+	}
+	if m.state.GlobalNav {
+		info += "  nav:global"
+	}
+	if m.pollPaused {
+		info += "  " + m.styles.warn.Render("poll paused")
+	}
+	if m.workDir != "" {
+		info += "  dir:" + filepath.Base(m.workDir)
+	}
+	if m.cmdLog != nil && !m.cmdLog.active() {
+		info += "  " + m.styles.warn.Render("log failed")
+	}
+	right := m.styles.headerInfo.Render(fmt.Sprintf("%s  %s", info, statusStyle.Render(statusText)))
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
 }
@@ -600,16 +1897,61 @@ func (m model) renderMain() string {
 	rightW := max(30, m.width-leftW-midW-4)
 	height := max(8, m.height-6)
 
-	catPane := m.renderCategories(leftW, height)
-	cmdPane := m.renderCommands(midW, height)
-	outPane := m.renderOutput(rightW, height)
+	if m.whatsNew != "" {
+		return m.renderWhatsNew(max(20, m.width-2), height)
+	}
+	if m.helpOpen {
+		return m.renderHelp(max(20, m.width-2), height)
+	}
+	if m.logOpen {
+		return m.renderLog(max(20, m.width-2), height)
+	}
+	if m.zoomed {
+		return m.renderOutput(max(20, m.width-2), height)
+	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, catPane, cmdPane, outPane)
+	if m.state.HideCategories {
+		rightW += leftW
+	}
+	panes := map[int]func() string{
+		0: func() string { return m.renderCategories(leftW, height) },
+		1: func() string { return m.renderCommands(midW, height) },
+		2: func() string { return m.renderOutput(rightW, height) },
+	}
+	var row []string
+	for _, p := range m.paneOrder() {
+		row = append(row, panes[p]())
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, row...)
+}
+
+// paneOrder lists pane ids left to right as currently rendered.
+func (m model) paneOrder() []int {
+	order := []int{0, 1, 2}
+	if m.state.SwapPanes {
+		order = []int{0, 2, 1}
+	}
+	if m.state.HideCategories {
+		order = order[1:]
+	}
+	return order
+}
+
+// nextPane returns the pane step positions away from the focused one in
+// visual order, wrapping at the ends.
+func (m model) nextPane(step int) int {
+	order := m.paneOrder()
+	for i, p := range order {
+		if p == m.focusPane {
+			return order[(i+step+len(order))%len(order)]
+		}
+	}
+	return order[0]
 }
 
 func (m model) paneStyleForFocus(pane int, width, height int) lipgloss.Style {
 	s := m.styles.pane.Width(width).Height(height)
-	if m.focusPane == pane && !m.formMode && !m.cmdMode && !m.filterMode {
+	if m.focusPane == pane && !m.formMode && !m.cmdMode && !m.filterMode && !m.pickerMode && !m.flagsMode && !m.promptMode && !m.confirmMode && !m.loginPrompt && !m.logoutPrompt && len(m.pasteSteps) == 0 && m.wizard == nil {
 		s = s.BorderForeground(lipgloss.Color("39"))
 	}
 	return s
@@ -619,6 +1961,10 @@ func (m model) renderCategories(width, height int) string {
 	var b strings.Builder
 	b.WriteString(m.styles.paneTitle.Render("Resources"))
 	b.WriteString("\n")
+	if len(m.categories) == 0 {
+		b.WriteString(m.styles.dim.Render("No categories"))
+		b.WriteString("\n")
+	}
 	for i, c := range m.categories {
 		line := fmt.Sprintf("%d %s", i+1, c.name)
 		if i == m.catIdx {
@@ -631,6 +1977,21 @@ func (m model) renderCategories(width, height int) string {
 	return m.paneStyleForFocus(0, width, height).Render(b.String())
 }
 
+// emptyCommandsText explains why the command list is empty.
+func (m model) emptyCommandsText() string {
+	switch {
+	case len(m.categories) == 0:
+		return "No commands available (check categories.hidden in config)"
+	case m.catIdx < 0 || m.catIdx >= len(m.categories) || len(m.categories[m.catIdx].commands) == 0:
+		return "No commands in this category"
+	case strings.TrimSpace(m.filterInput.Value()) != "" || m.filterTag != "":
+		return "No commands match filter"
+	case !m.showAdvanced:
+		return "Only advanced commands here (a to show)"
+	}
+	return "No commands available"
+}
+
 func (m model) renderCommands(width, height int) string {
 	cmds := m.visibleCommands()
 	if m.cmdIdx >= len(cmds) {
@@ -638,71 +1999,324 @@ func (m model) renderCommands(width, height int) string {
 	}
 
 	var b strings.Builder
-	b.WriteString(m.styles.paneTitle.Render("Commands"))
+	title := "Commands"
+	if m.state.HideCategories && m.catIdx >= 0 && m.catIdx < len(m.categories) {
+		// the categories pane is collapsed, so name the category here
+		title = fmt.Sprintf("%d %s", m.catIdx+1, m.categories[m.catIdx].name)
+	}
+	if m.showAdvanced {
+		title += " (+advanced)"
+	}
+	if m.catIdx >= 0 && m.catIdx < len(m.categories) {
+		if order := m.state.CommandOrder[m.categories[m.catIdx].name]; order != "" {
+			title += " [" + order + "]"
+		}
+	}
+	b.WriteString(m.styles.paneTitle.Render(title))
 	b.WriteString("\n")
 	if strings.TrimSpace(m.filterInput.Value()) != "" {
-		b.WriteString(m.styles.dim.Render("filter: " + m.filterInput.Value()))
+		label := "filter: "
+		if m.filterRegex {
+			label = "regex: "
+		}
+		b.WriteString(m.styles.dim.Render(label + m.filterInput.Value()))
+		b.WriteString("\n")
+		if m.filterErr != "" {
+			b.WriteString(m.styles.dim.Render(m.filterErr))
+			b.WriteString("\n")
+		}
+	}
+	if s := m.filterSummary(); s != "" {
+		b.WriteString(m.styles.dim.Render(s))
 		b.WriteString("\n")
 	}
 
+	// text width inside the pane's horizontal padding
+	footer := func(text string) {
+		b.WriteString(m.footerLine(text, width-2))
+		b.WriteString("\n")
+	}
 	if len(cmds) == 0 {
-		b.WriteString(m.styles.dim.Render("No commands match filter"))
+		b.WriteString(m.styles.dim.Render(m.emptyCommandsText()))
 		b.WriteString("\n")
 	} else {
+		catalogIdx := m.catalogIndices()
 		for i, c := range cmds {
 			line := fmt.Sprintf("%-14s %s", c.name, c.description)
-			if i == m.cmdIdx {
+			if m.compactCommands {
+				line = c.name
+			}
+			if m.showIndices {
+				line = fmt.Sprintf("%2d %s", catalogIdx[c.name], line)
+			}
+			if f := m.favoriteIndex(c.name); f >= 0 {
+				line += fmt.Sprintf(" ★%d", f+1)
+			}
+			locked := m.safeMode && c.destructive
+			if locked {
+				line += " [locked]"
+			}
+			switch {
+			case i == m.cmdIdx:
 				b.WriteString(m.styles.selected.Render(line))
-			} else {
+			case locked:
+				b.WriteString(m.styles.dim.Render(line))
+			default:
 				b.WriteString(m.styles.normal.Render(line))
 			}
 			b.WriteString("\n")
 		}
 		b.WriteString("\n")
 		selected := cmds[m.cmdIdx]
-		b.WriteString(m.styles.dim.Render("selected: " + strings.Join(append([]string{"devtunnel"}, selected.baseArgs...), " ")))
-		b.WriteString("\n")
+		if selected.workflow != nil {
+			for i, step := range selected.workflow.Steps {
+				footer(fmt.Sprintf("step %d: devtunnel %s", i+1, step))
+			}
+		} else {
+			footer("selected: " + strings.Join(append([]string{"devtunnel"}, selected.baseArgs...), " "))
+		}
 		if selected.example != "" {
-			b.WriteString(m.styles.dim.Render("example: devtunnel " + selected.example))
-			b.WriteString("\n")
+			footer("example: devtunnel " + selected.example)
+		}
+		if len(selected.aliases) > 0 {
+			footer("aliases: " + strings.Join(selected.aliases, ", "))
+		}
+		if m.showInspector {
+			b.WriteString(m.renderInspector(selected))
 		}
 	}
 
 	return m.paneStyleForFocus(1, width, height).Render(b.String())
 }
 
+// catalogIndices maps each listed command to its 1-based position in its
+// category, which stays the same whatever the filter, order or advanced
+// toggle.
+func (m model) catalogIndices() map[string]int {
+	out := map[string]int{}
+	for ci, cat := range m.categories {
+		if ci != m.catIdx && !m.filterAll {
+			continue
+		}
+		for i, c := range cat.commands {
+			out[c.name] = i + 1
+		}
+	}
+	return out
+}
+
+// footerLine renders a dim detail line under the command list, cut to width
+// with an ellipsis, or wrapped onto further lines when wrapFooter is on.
+func (m model) footerLine(text string, width int) string {
+	if width <= 0 {
+		return m.styles.dim.Render(text)
+	}
+	if m.wrapFooter {
+		return m.styles.dim.Render(ansi.Wrap(text, width, " "))
+	}
+	return m.styles.dim.Render(ansi.Truncate(text, width, "…"))
+}
+
+// inspectArgs returns the argv that would be handed to exec.Command for the
+// current input: the open form, the command line, or the selected command.
+func (m model) inspectArgs(selected commandItem) []string {
+	switch {
+	case m.formMode && m.formCmd != nil && m.formCmd.workflow == nil:
+		parts, params, _ := m.formArgs()
+		secrets := m.secrets.with()
+		nParams := len(m.formCmd.params)
+		for i, name := range m.formCmd.required {
+			if !m.formCmd.isSensitive(name) {
+				continue
+			}
+			if i < nParams {
+				// substituted into baseArgs, so masked by value
+				if v := params[name]; v != "" {
+					secrets[v] = true
+				}
+				continue
+			}
+			// appended after baseArgs in order, past the params
+			if j := len(m.formCmd.baseArgs) + 1 + i - nParams; j < len(parts) {
+				parts[j] = redacted
+			}
+		}
+		return secrets.redactArgs(parts)
+	case m.wizard != nil:
+		return m.secrets.redactArgs(argValues(m.wizardArgs()))
+	case m.flagsMode && m.flagsCmd != nil:
+		parts := append([]string{"devtunnel"}, m.flagsCmd.baseArgs...)
+		return m.secrets.redactArgs(m.withCluster(m.flagsCmd, append(parts, splitArgs(m.flagsInput.Value())...)))
+	case m.cmdMode && m.shellInput():
+		return []string{"sh", "-c", strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(m.cmdInput.Value()), "!"))}
+	case m.cmdMode:
+		return m.secrets.redactArgs(append([]string{"devtunnel"}, splitArgs(m.cmdInput.Value())...))
+	case selected.workflow != nil:
+		return nil
+	}
+	return m.withCluster(&selected, append([]string{"devtunnel"}, selected.baseArgs...))
+}
+
+func (m model) renderInspector(selected commandItem) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(m.styles.paneTitle.Render("Args"))
+	b.WriteString("\n")
+	args := m.inspectArgs(selected)
+	if len(args) == 0 {
+		b.WriteString(m.styles.dim.Render("workflow: args are built per step"))
+		b.WriteString("\n")
+	}
+	for i, arg := range args {
+		b.WriteString(m.styles.dim.Render(fmt.Sprintf("%2d  %q", i, arg)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 func (m model) renderOutput(width, height int) string {
 	var b strings.Builder
-	b.WriteString(m.styles.paneTitle.Render("Output"))
+	b.WriteString(m.outputTitle(m.viewport.Width))
 	b.WriteString("\n")
-	b.WriteString(m.viewport.View())
+	if m.pinned != "" {
+		b.WriteString(m.renderPinned(m.viewport.Width))
+	}
+	b.WriteString(m.outputView())
 	return m.paneStyleForFocus(2, width, height).Render(b.String())
 }
 
 func (m model) renderBottomBar() string {
+	if m.whatsNew != "" {
+		return m.styles.cmdline.Render("Press any key to continue")
+	}
+	if m.helpOpen {
+		return m.styles.cmdline.Render("↑/↓ select a key to see what it does, Esc or ? close")
+	}
+	if m.logOpen {
+		return m.styles.cmdline.Render("↑/↓ select, Enter show output, Tab group by command / as run, Esc or L close")
+	}
+	if m.pickerMode {
+		return m.renderPicker()
+	}
+	if m.loginPrompt {
+		return m.renderLoginPrompt()
+	}
+	if m.logoutPrompt {
+		return m.renderLogoutPrompt()
+	}
+	if len(m.pasteSteps) > 0 {
+		return m.renderPasteConfirm()
+	}
+	if m.confirmMode {
+		return m.renderConfirm()
+	}
 	if m.formMode {
 		return m.renderFormOverlay()
 	}
+	if m.wizard != nil {
+		return m.renderWizard()
+	}
 	if m.cmdMode {
-		return m.styles.cmdline.Render(m.cmdInput.View() + "  (Enter run, Esc cancel)")
+		if m.shellInput() {
+			return m.styles.shellLine.Render(m.cmdInput.View() + shellHint)
+		}
+		return m.styles.cmdline.Render(m.cmdInput.View() + cmdHint)
 	}
 	if m.filterMode {
-		return m.styles.cmdline.Render(m.filterInput.View() + "  (Enter apply, Esc cancel)")
+		return m.styles.cmdline.Render(m.filterInput.View() + filterHint)
+	}
+	if m.flagsMode {
+		return m.styles.cmdline.Render(m.flagsInput.View() + flagsHint)
+	}
+	if m.promptMode {
+		return m.styles.cmdline.Render(m.promptInput.View() + promptHint)
 	}
 
-	help := []string{
-		m.styles.hotkey.Render("←/→") + " category",
-		m.styles.hotkey.Render("↑/↓") + " command",
-		m.styles.hotkey.Render("enter") + " run",
-		m.styles.hotkey.Render(":") + " raw cmd",
-		m.styles.hotkey.Render("/") + " filter",
-		m.styles.hotkey.Render("u/d") + " output scroll",
-		m.styles.hotkey.Render("r") + " rerun",
-		m.styles.hotkey.Render("q") + " quit",
+	help := m.barHints()
+	if m.state.ShowCommand {
+		help = []string{
+			m.styles.hotkey.Render("enter") + " " + m.commandPreview(),
+			m.styles.hotkey.Render("A") + " hints",
+		}
+	}
+	if len(m.outcomes) > 0 {
+		help = append([]string{m.renderOutcomes()}, help...)
 	}
 	return m.styles.statusBar.Render(strings.Join(help, "  "))
 }
 
+const maxOutcomes = 10
+
+// commandPreview shows what Enter would run for the selected command, with
+// fields still to be filled in as <placeholders>.
+func (m model) commandPreview() string {
+	cmds := m.visibleCommands()
+	if len(cmds) == 0 {
+		return m.styles.dim.Render("no command selected")
+	}
+	cmd := cmds[min(m.cmdIdx, len(cmds)-1)]
+	switch {
+	case cmd.workflow != nil:
+		return fmt.Sprintf("workflow %s (%d steps)", cmd.name, len(cmd.workflow.Steps))
+	case len(cmd.wizard) > 0:
+		return fmt.Sprintf("guided devtunnel %s (%d steps)", strings.Join(cmd.baseArgs, " "), len(cmd.wizard))
+	case len(cmd.baseArgs) == 0:
+		return "open command mode"
+	}
+	parts := append([]string{"devtunnel"}, cmd.baseArgs...)
+	for _, r := range cmd.required[len(cmd.params):] {
+		parts = append(parts, "<"+r+">")
+	}
+	for _, mf := range cmd.multi {
+		switch {
+		case mf.single:
+			parts = append(parts, strings.TrimSpace(mf.flag+" <"+strings.Join(mf.options, "|")+">"))
+		default:
+			parts = append(parts, strings.TrimSpace(mf.flag+" <"+mf.label+">..."))
+		}
+	}
+	if cmd.optional != "" {
+		parts = append(parts, "["+cmd.optional+"]")
+	}
+	return strings.Join(m.withCluster(&cmd, parts), " ")
+}
+
+// renderOutcomes draws one dot per recent command, green for success and
+// red for failure, oldest first.
+func (m model) renderOutcomes() string {
+	var b strings.Builder
+	for _, ok := range m.outcomes {
+		if ok {
+			b.WriteString(m.styles.ok.Render("●"))
+		} else {
+			b.WriteString(m.styles.err.Render("●"))
+		}
+	}
+	return b.String()
+}
+
+// lastRunHint tells how long ago the last command started, so a rerun can
+// be judged worthwhile.
+func (m model) lastRunHint() string {
+	if m.lastRunAt.IsZero() || len(m.lastCmd) == 0 {
+		return ""
+	}
+	return " " + m.styles.dim.Render("(last: "+agoText(time.Since(m.lastRunAt))+")")
+}
+
+func agoText(d time.Duration) string {
+	switch {
+	case d < 5*time.Second:
+		return "just now"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+}
+
 func (m model) renderFormOverlay() string {
 	if m.formCmd == nil {
 		return m.styles.cmdline.Render("form unavailable")
@@ -713,9 +2327,22 @@ func (m model) renderFormOverlay() string {
 	b.WriteString("\n")
 	b.WriteString("Field " + fmt.Sprintf("%d/%d", m.formIndex+1, len(m.formInputs)) + " - " + m.formLabels[m.formIndex])
 	b.WriteString("\n")
+	if c := m.formChecks[m.formIndex]; c.field != nil {
+		b.WriteString(m.renderChecklist(c))
+		b.WriteString("\n")
+		if c.field.single {
+			b.WriteString("←/→ choose, Enter next/run, Shift+Tab back, Ctrl+R first field, Ctrl+Y copy as shell, Esc cancel")
+			return m.styles.cmdline.Render(b.String())
+		}
+		b.WriteString("Space toggle, ←/→ move, Enter next/run, Shift+Tab back, Ctrl+R first field, Ctrl+Y copy as shell, Esc cancel")
+		return m.styles.cmdline.Render(b.String())
+	}
 	b.WriteString(m.formInputs[m.formIndex].View())
 	b.WriteString("\n")
-	b.WriteString("Enter next/run, Esc cancel")
+	b.WriteString("Enter next/run, Shift+Tab back, Ctrl+R first field, Ctrl+Y copy as shell, Esc cancel")
+	if m.captured != "" {
+		b.WriteString(", Ctrl+O insert " + m.captured)
+	}
 
 	return m.styles.cmdline.Render(b.String())
 }
@@ -735,8 +2362,20 @@ func max(a, b int) int {
 }
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	showVersion := flag.Bool("version", false, "print version and exit")
+	flag.Parse()
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+
+	m := initialModel()
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err := p.Run()
+	// kill and reap anything still running so hosts don't outlive the TUI
+	m.procs.shutdown(3 * time.Second)
+	m.cmdLog.close()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// markdownResult renders a result as a fenced code block captioned with
+// the command. The fence is longer than any backtick run in the output so
+// the block cannot be closed early.
+func markdownResult(cmdText, output string) string {
+	output = strings.TrimRight(ansi.Strip(output), "\n")
+	fence := "```"
+	for strings.Contains(output, fence) {
+		fence += "`"
+	}
+	var b strings.Builder
+	b.WriteString("`$ " + cmdText + "`\n\n")
+	b.WriteString(fence + "text\n")
+	b.WriteString(output)
+	b.WriteString("\n" + fence + "\n")
+	return b.String()
+}
+
+// copyMarkdown copies the latest result as Markdown. Without a clipboard
+// tool the text is written to a file in the working directory instead,
+// since the OSC 52 fallback cannot tell whether it worked.
+func (m model) copyMarkdown() model {
+	if m.lastText == "" || m.running {
+		m.statusErr = true
+		m.statusText = "no finished command to copy"
+		return m
+	}
+	text := markdownResult(m.lastText, m.lastOutput)
+	if err := clipboard.WriteAll(text); err == nil {
+		m.statusErr = false
+		m.statusText = "copied result as Markdown"
+		return m
+	}
+	path, err := filepath.Abs("devtunnel-tui-output-" + time.Now().Format("20060102-150405") + ".md")
+	if err == nil {
+		err = os.WriteFile(path, []byte(text), 0o600)
+	}
+	if err != nil {
+		m.statusErr = true
+		m.statusText = "no clipboard and could not write file: " + err.Error()
+		return m
+	}
+	m.statusErr = false
+	m.statusText = "no clipboard available, Markdown written to " + path
+	return m
+}
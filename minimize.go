@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// toggleMinimized collapses the TUI to one inline status line below the
+// shell prompt, leaving the alt screen so the terminal is usable above it,
+// or restores the full layout.
+func (m model) toggleMinimized() (tea.Model, tea.Cmd) {
+	m.minimized = !m.minimized
+	if m.minimized {
+		return m, tea.ExitAltScreen
+	}
+	return m.layoutViewport(), tea.EnterAltScreen
+}
+
+// updateMinimized handles keys while minimized: only restoring and
+// quitting do anything.
+func (m model) updateMinimized(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case k.String() == "-" || k.Type == tea.KeyEnter || k.Type == tea.KeyEsc:
+		return m.toggleMinimized()
+	case k.Type == tea.KeyCtrlC && m.running && m.interrupt != nil:
+		m.interrupt()
+		m.statusErr = true
+		m.statusText = "interrupting (Ctrl-C again once stopped to quit)"
+	case k.Type == tea.KeyCtrlC || k.String() == "q":
+		return m, tea.Sequence(m.titleCmd(""), tea.Quit)
+	}
+	return m, nil
+}
+
+// renderMinimized is the single status line shown while minimized.
+func (m model) renderMinimized() string {
+	line := "devtunnel-tui"
+	if m.haveCount {
+		line += fmt.Sprintf("  tunnels:%d", m.tunnelCount)
+	}
+	status := m.statusText
+	switch {
+	case m.running:
+		status = m.spinnerView() + " " + status + " (" + time.Since(m.runStart).Round(time.Second).String() + ")"
+		status = m.styles.warn.Render(status)
+	case m.statusErr:
+		status = m.styles.err.Render(status)
+	}
+	line += "  " + status + "  " + m.styles.dim.Render("- expand, q quit")
+	if m.width > 0 {
+		line = ansi.Truncate(line, m.width, "…")
+	}
+	return line
+}
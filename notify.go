@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notifyCmd rings the terminal bell (and optionally raises a desktop
+// notification) when a command ran for at least the configured threshold.
+func notifyCmd(cfg notifyConfig, cmdText string, failed bool, elapsed time.Duration) tea.Cmd {
+	if !cfg.Enabled || elapsed < time.Duration(cfg.MinSeconds)*time.Second {
+		return nil
+	}
+	result := "completed"
+	if failed {
+		result = "failed"
+	}
+	body := fmt.Sprintf("%s %s after %s", cmdText, result, elapsed.Round(time.Second))
+	return func() tea.Msg {
+		fmt.Fprint(os.Stdout, "\a")
+		if cfg.Desktop {
+			_ = desktopNotify("DevTunnels TUI", body)
+		}
+		return nil
+	}
+}
+
+func desktopNotify(title, body string) error {
+	if runtime.GOOS == "darwin" {
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		return exec.Command("osascript", "-e", script).Run()
+	}
+	return exec.Command("notify-send", title, body).Run()
+}
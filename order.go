@@ -0,0 +1,70 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// Command list orders, stored per category in state.json. The default
+// (built-in) order is not stored.
+const (
+	orderReverse = "reverse"
+	orderAlpha   = "alpha"
+)
+
+// sortCommands applies a category's saved order to its command list.
+func sortCommands(items []commandItem, order string) []commandItem {
+	switch order {
+	case orderReverse:
+		items = slices.Clone(items)
+		slices.Reverse(items)
+	case orderAlpha:
+		items = slices.Clone(items)
+		slices.SortStableFunc(items, func(a, b commandItem) int {
+			return strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name))
+		})
+	}
+	return items
+}
+
+// cycleOrder steps the current category through built-in, reversed and
+// alphabetical order, keeping the same command selected.
+func (m model) cycleOrder() model {
+	if m.catIdx < 0 || m.catIdx >= len(m.categories) {
+		return m
+	}
+	var selected string
+	if cmds := m.visibleCommands(); len(cmds) > 0 {
+		selected = cmds[min(m.cmdIdx, len(cmds)-1)].name
+	}
+
+	cat := m.categories[m.catIdx].name
+	next := ""
+	switch m.state.CommandOrder[cat] {
+	case "":
+		next = orderReverse
+	case orderReverse:
+		next = orderAlpha
+	}
+	if m.state.CommandOrder == nil {
+		m.state.CommandOrder = map[string]string{}
+	}
+	if next == "" {
+		delete(m.state.CommandOrder, cat)
+	} else {
+		m.state.CommandOrder[cat] = next
+	}
+
+	for i, c := range m.visibleCommands() {
+		if c.name == selected {
+			m.cmdIdx = i
+		}
+	}
+	m.statusErr = false
+	m.statusText = "order: " + valueOr(next, "default")
+	if err := saveState(m.state); err != nil {
+		m.statusErr = true
+		m.statusText = "could not save layout: " + err.Error()
+	}
+	return m
+}
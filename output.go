@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// setOutput replaces the output pane content, keeping the line cursor in
+// range. Callers still decide where to scroll.
+func (m model) setOutput(content string) model {
+	content = sanitizeOutput(content)
+	m.outLines, m.links = m.styles.linkify(strings.Split(content, "\n"))
+	m.linkIdx = -1
+	m.outRaw = nil
+	m.visual = false
+	m.outNotLatest = false
+	m.outCursor = min(m.outCursor, len(m.outLines)-1)
+	m.viewport.SetContent(strings.Join(m.outLines, "\n"))
+	return m.findMatches()
+}
+
+// moveCursor moves the output line cursor by delta, first pulling it into
+// the visible window in case the view was scrolled, and scrolls to keep it
+// visible.
+func (m model) moveCursor(delta int) model {
+	top := m.viewport.YOffset
+	bottom := top + m.viewport.Height - 1
+	m.outCursor = max(top, min(m.outCursor, bottom))
+	return m.setCursor(m.outCursor + delta)
+}
+
+// countOr1 reads a count prefix, where none means 1.
+func countOr1(count string) int {
+	if n, err := strconv.Atoi(count); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func (m model) setCursor(line int) model {
+	m.outCursor = max(0, min(line, len(m.outLines)-1))
+	if m.outCursor < m.viewport.YOffset {
+		m.viewport.SetYOffset(m.outCursor)
+	} else if m.outCursor >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(m.outCursor - m.viewport.Height + 1)
+	}
+	return m
+}
+
+// cursorText returns the plain text of the line under the cursor, without
+// display-only decoration such as timestamps.
+func (m model) cursorText() string {
+	return m.lineText(m.outCursor)
+}
+
+// lineText returns the plain text of output line i.
+func (m model) lineText(i int) string {
+	if i < 0 || i >= len(m.outLines) {
+		return ""
+	}
+	if i < len(m.outRaw) {
+		return ansi.Strip(sanitizeOutput(m.outRaw[i]))
+	}
+	return ansi.Strip(m.outLines[i])
+}
+
+// outputView renders the viewport, highlighting the cursor line while the
+// output pane has focus.
+func (m model) outputView() string {
+	if m.focusPane != 2 || len(m.outLines) == 0 {
+		return m.viewport.View()
+	}
+	vp := m.viewport
+	lines := append([]string(nil), m.outLines...)
+	if m.visual {
+		lo, hi := m.visualRange()
+		for i := lo; i <= hi; i++ {
+			lines[i] = m.styles.selected.Render(ansi.Strip(lines[i]))
+		}
+	}
+	lines[m.outCursor] = m.styles.cursorLine.Render(ansi.Strip(lines[m.outCursor]))
+	if m.state.RelativeNumbers {
+		// The cursor line shows its own number, the others how far away
+		// they are, ready for a count before j/k. The gutter comes out of
+		// the line's width so nothing wraps and the cursor stays in step.
+		w := len(strconv.Itoa(len(lines)))
+		text := max(0, vp.Width-w-1)
+		for i := range lines {
+			n := i - m.outCursor
+			if n == 0 {
+				n = i + 1
+			}
+			lines[i] = m.styles.dim.Render(fmt.Sprintf("%*d ", w, abs(n))) + ansi.Truncate(lines[i], text, "")
+		}
+	}
+	vp.SetContent(strings.Join(lines, "\n"))
+	return vp.View()
+}
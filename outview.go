@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// outputStream selects which captured stream the output pane shows.
+type outputStream int
+
+const (
+	streamCombined outputStream = iota
+	streamStdout
+	streamStderr
+)
+
+func (s outputStream) String() string {
+	switch s {
+	case streamStdout:
+		return "stdout"
+	case streamStderr:
+		return "stderr"
+	}
+	return "combined"
+}
+
+// resultBody returns the latest result in the selected stream.
+func (m model) resultBody() string {
+	switch m.outStream {
+	case streamStdout:
+		return m.lastStdout
+	case streamStderr:
+		return m.lastStderr
+	}
+	return m.lastOutput
+}
+
+// cycleOutputStream switches the output pane between combined, stdout-only
+// and stderr-only views of the latest result.
+func (m model) cycleOutputStream() model {
+	if m.lastText == "" || m.running {
+		m.statusErr = true
+		m.statusText = "no finished command to switch streams for"
+		return m
+	}
+	if !m.haveStreams {
+		m.statusErr = true
+		m.statusText = "separate streams are not available for " + m.lastText
+		return m
+	}
+	m.outStream = (m.outStream + 1) % 3
+	body := m.resultBody()
+	if body == "" {
+		body = m.styles.dim.Render("(no " + m.outStream.String() + " output)")
+	} else {
+		body = m.formatOutput(m.lastText, body)
+	}
+	m = m.setOutput("$ " + m.lastText + "\n\n" + body)
+	m.viewport.GotoTop()
+	m.statusErr = false
+	m.statusText = "showing " + m.outStream.String() + " output"
+	return m
+}
+
+// literalArgs returns the baseArgs before the first {placeholder}, the part
+// that reads the same on every run.
+func (c commandItem) literalArgs() []string {
+	for i, a := range c.baseArgs {
+		if placeholderRe.MatchString(a) {
+			return c.baseArgs[:i]
+		}
+	}
+	return c.baseArgs
+}
+
+// showsSelected reports whether the latest result came from the selected
+// command, matching its literal subcommand words or workflow name.
+func (m model) showsSelected() bool {
+	cmds := m.visibleCommands()
+	if m.lastText == "" || m.cmdIdx >= len(cmds) {
+		return true
+	}
+	cmd := cmds[m.cmdIdx]
+	if cmd.workflow != nil {
+		return m.lastText == "workflow "+cmd.name
+	}
+	lit := cmd.literalArgs()
+	n := len(lit)
+	return len(m.lastCmd) > n && m.lastCmd[0] == "devtunnel" && slices.Equal(m.lastCmd[1:n+1], lit)
+}
+
+// outputTitle names the output pane, noting the command that produced the
+// result when the selection has moved on.
+func (m model) outputTitle(width int) string {
+	title := "Output"
+	if m.outStream != streamCombined {
+		title += " [" + m.outStream.String() + "]"
+	}
+	if m.paged {
+		title += fmt.Sprintf(" page %d/%d", m.currentPage()+1, m.pageCount())
+	}
+	title += m.matchTitle()
+	title += m.visualTitle()
+	title = m.styles.paneTitle.Render(title) + m.sourceTitle()
+	if m.running || m.showsSelected() {
+		return title
+	}
+	note := "  showing result of: " + m.lastText
+	return title + m.styles.dim.Render(ansi.Truncate(note, max(0, width-ansi.StringWidth(title)), "…"))
+}
@@ -0,0 +1,41 @@
+package main
+
+// pageCount is the number of viewport-height pages the output spans.
+func (m model) pageCount() int {
+	h := max(1, m.viewport.Height)
+	return max(1, (m.viewport.TotalLineCount()+h-1)/h)
+}
+
+// currentPage is the zero-based page at the top of the viewport. The last
+// page may overlap the one before it, since the viewport cannot scroll past
+// the end.
+func (m model) currentPage() int {
+	if m.viewport.AtBottom() {
+		return m.pageCount() - 1
+	}
+	return m.viewport.YOffset / max(1, m.viewport.Height)
+}
+
+// turnPage moves the output by delta whole pages, clamped to the first and
+// last page.
+func (m model) turnPage(delta int) model {
+	page := max(0, min(m.currentPage()+delta, m.pageCount()-1))
+	m.viewport.SetYOffset(page * m.viewport.Height)
+	if m.focusPane == 2 {
+		m = m.setCursor(m.viewport.YOffset)
+	}
+	return m
+}
+
+// togglePaged switches the output pane between continuous scrolling and
+// discrete pages turned with n/p.
+func (m model) togglePaged() model {
+	m.paged = !m.paged
+	m.statusErr = false
+	m.statusText = "continuous output scrolling"
+	if m.paged {
+		m.statusText = "paged output: n next page, p previous page"
+		m = m.turnPage(0)
+	}
+	return m
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pastedCommands splits a multi-line paste into devtunnel invocations.
+// Blank lines and # comments are skipped, and a leading "$ " or
+// "devtunnel" is dropped, as when copying from documentation.
+func (m model) pastedCommands(text string) [][]string {
+	var steps [][]string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "$ "))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := splitArgs(line)
+		if fields[0] == "devtunnel" {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		steps = append(steps, append([]string{"devtunnel"}, m.expandAlias(fields)...))
+	}
+	return steps
+}
+
+// handlePaste offers to run a pasted block as a sequence when it holds more
+// than one command. It reports false for ordinary single-line pastes.
+func (m model) handlePaste(k tea.KeyMsg) (model, bool) {
+	if !k.Paste || !strings.ContainsAny(string(k.Runes), "\r\n") {
+		return m, false
+	}
+	steps := m.pastedCommands(string(k.Runes))
+	if len(steps) < 2 {
+		if len(steps) == 1 {
+			m.cmdInput.SetValue(strings.Join(steps[0][1:], " "))
+			m.cmdInput.CursorEnd()
+		}
+		return m, true
+	}
+	// The sequence is confirmed as a whole, so each step gets the flag that
+	// skips its own devtunnel prompt, listed in the confirmation.
+	for i, step := range steps {
+		steps[i] = m.forced(step)
+	}
+	m.cmdMode = false
+	m.cmdInput.Blur()
+	m.pasteSteps = steps
+	return m, true
+}
+
+func (m model) updatePasteConfirm(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "enter":
+		steps := m.pasteSteps
+		m.pasteSteps = nil
+		if !m.devtunnelFound {
+			m.statusErr = true
+			m.statusText = "install devtunnel CLI first"
+			return m, nil
+		}
+		return m.startSequence(fmt.Sprintf("paste (%d commands)", len(steps)), steps)
+	case "esc", "n", "q":
+		m.pasteSteps = nil
+		m.statusErr = false
+		m.statusText = "paste cancelled"
+	}
+	return m, nil
+}
+
+// startSequence runs steps one after another through the workflow runner,
+// which reports progress and stops at the first failure.
+func (m model) startSequence(name string, steps [][]string) (tea.Model, tea.Cmd) {
+	m.workflow = &workflowRun{name: name, steps: steps, start: time.Now()}
+	return m, m.runCommandCmd(steps[0])
+}
+
+func (m model) renderPasteConfirm() string {
+	var b strings.Builder
+	b.WriteString(m.styles.warn.Render(fmt.Sprintf("Run %d pasted commands in sequence?", len(m.pasteSteps))))
+	b.WriteString("\n")
+	shown := min(len(m.pasteSteps), pickerRows)
+	for i := 0; i < shown; i++ {
+		b.WriteString(fmt.Sprintf("%2d  %s\n", i+1, m.secrets.displayCmd(m.pasteSteps[i])))
+	}
+	if more := len(m.pasteSteps) - shown; more > 0 {
+		b.WriteString(m.styles.dim.Render(fmt.Sprintf("    … %d more", more)))
+		b.WriteString("\n")
+	}
+	b.WriteString("Enter run all (stops at first failure), Esc cancel")
+	return m.styles.cmdline.Render(b.String())
+}
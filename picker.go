@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type pickerKind int
+
+const (
+	pickCluster pickerKind = iota
+	pickBookmark
+	pickCategory
+	pickJob
+	pickEncoding
+)
+
+// picker is a list overlay. A fuzzy picker narrows items as the user types;
+// idx then counts within the matching items.
+type picker struct {
+	kind  pickerKind
+	title string
+	items []string
+	idx   int
+	fuzzy bool
+	query string
+}
+
+// shown returns the indexes of the items matching the query.
+func (p picker) shown() []int {
+	out := make([]int, 0, len(p.items))
+	for i, item := range p.items {
+		if !p.fuzzy || fuzzyMatch(p.query, item) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// fuzzyMatch reports whether the letters of query appear in s in order,
+// ignoring case and spaces.
+func fuzzyMatch(query, s string) bool {
+	rs := []rune(strings.ToLower(s))
+	for _, q := range strings.ToLower(query) {
+		if q == ' ' {
+			continue
+		}
+		i := 0
+		for i < len(rs) && rs[i] != q {
+			i++
+		}
+		if i == len(rs) {
+			return false
+		}
+		rs = rs[i+1:]
+	}
+	return true
+}
+
+const pickerRows = 8
+
+func (m model) openPicker(kind pickerKind, title string, items []string, idx int) model {
+	m.pickerMode = true
+	m.picker = picker{kind: kind, title: title, items: items, idx: max(0, min(idx, len(items)-1))}
+	return m
+}
+
+// openFuzzyPicker opens a picker that filters its items as the user types.
+func (m model) openFuzzyPicker(kind pickerKind, title string, items []string, idx int) model {
+	m = m.openPicker(kind, title, items, idx)
+	m.picker.fuzzy = true
+	return m
+}
+
+func (m model) updatePicker(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	shown := m.picker.shown()
+	key := k.String()
+	if m.picker.fuzzy {
+		// letters go to the query, so only arrows and ctrl keys navigate
+		switch {
+		case k.Type == tea.KeyRunes || k.Type == tea.KeySpace:
+			m.picker.query += string(k.Runes)
+			m.picker.idx = 0
+			return m, nil
+		case k.Type == tea.KeyBackspace:
+			if q := []rune(m.picker.query); len(q) > 0 {
+				m.picker.query = string(q[:len(q)-1])
+				m.picker.idx = 0
+			}
+			return m, nil
+		case key == "ctrl+p":
+			key = "up"
+		case key == "ctrl+n":
+			key = "down"
+		}
+	}
+	switch key {
+	case "esc", "q":
+		m.pickerMode = false
+		return m, nil
+	case "p":
+		if m.picker.kind == pickCluster {
+			return m.measureLatency()
+		}
+	case "up", "k":
+		if m.picker.idx > 0 {
+			m.picker.idx--
+		}
+	case "down", "j":
+		if m.picker.idx < len(shown)-1 {
+			m.picker.idx++
+		}
+	case "enter":
+		m.pickerMode = false
+		if len(shown) == 0 {
+			return m, nil
+		}
+		i := shown[min(m.picker.idx, len(shown)-1)]
+		return m.pickerChosen(m.picker.kind, i, m.picker.items[i])
+	}
+	return m, nil
+}
+
+func (m model) pickerChosen(kind pickerKind, idx int, value string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case pickCluster:
+		m.cluster = ""
+		if f := strings.Fields(value); idx > 0 && len(f) > 0 {
+			m.cluster = f[0]
+		}
+		m.statusErr = false
+		m.statusText = "cluster: " + valueOr(m.cluster, "default")
+	case pickBookmark:
+		return m.jumpToBookmark(idx), nil
+	case pickCategory:
+		m.catIdx = idx
+		m.cmdIdx = 0
+		m.focusPane = 1
+		m.statusErr = false
+		m.statusText = "category: " + m.categories[idx].name
+	case pickJob:
+		return m.viewJob(idx), nil
+	case pickEncoding:
+		return m.exportRaw(idx), nil
+	}
+	return m, nil
+}
+
+func (m model) renderPicker() string {
+	shown := m.picker.shown()
+	var b strings.Builder
+	b.WriteString(m.picker.title)
+	if m.picker.fuzzy {
+		b.WriteString(": " + m.picker.query + "█")
+	}
+	b.WriteString("\n")
+	start := max(0, min(m.picker.idx-pickerRows/2, len(shown)-pickerRows))
+	end := min(len(shown), start+pickerRows)
+	for i := start; i < end; i++ {
+		marker := "  "
+		if i == m.picker.idx {
+			marker = "> "
+		}
+		b.WriteString(marker + m.picker.items[shown[i]])
+		b.WriteString("\n")
+	}
+	if len(shown) == 0 {
+		b.WriteString(m.styles.dim.Render("  no match"))
+		b.WriteString("\n")
+	}
+	hint := "↑/↓ move, Enter select, Esc cancel"
+	if m.picker.fuzzy {
+		hint = "type to filter, ↑/↓ move, Enter select, Esc cancel"
+	}
+	b.WriteString(fmt.Sprintf("%d/%d  %s", min(m.picker.idx+1, len(shown)), len(shown), hint))
+	return m.styles.cmdline.Render(b.String())
+}
+
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
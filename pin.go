@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxPinnedLines caps how much of a pinned result stays on screen so the
+// output pane keeps most of its height.
+const maxPinnedLines = 5
+
+// togglePin pins the line under the output cursor when the output pane has
+// focus, or the whole latest result otherwise. Pressed again it unpins.
+func (m model) togglePin() model {
+	if m.pinned != "" {
+		m.pinned = ""
+		m.pinnedFrom = ""
+		m.statusErr = false
+		m.statusText = "unpinned"
+		return m.layoutViewport()
+	}
+
+	text := strings.TrimSpace(ansi.Strip(m.lastOutput))
+	from := m.lastText
+	if m.focusPane == 2 {
+		text = strings.TrimSpace(m.cursorText())
+		from = fmt.Sprintf("%s, line %d", m.lastText, m.outCursor+1)
+	}
+	if text == "" {
+		m.statusErr = true
+		m.statusText = "nothing to pin"
+		return m
+	}
+	m.pinned = text
+	m.pinnedFrom = from
+	m.statusErr = false
+	m.statusText = "pinned (m to unpin)"
+	return m.layoutViewport()
+}
+
+// pinnedLines returns the pinned text as displayed, truncated to
+// maxPinnedLines with a marker for the rest.
+func (m model) pinnedLines() []string {
+	if m.pinned == "" {
+		return nil
+	}
+	lines := strings.Split(m.pinned, "\n")
+	if len(lines) > maxPinnedLines {
+		more := len(lines) - maxPinnedLines + 1
+		lines = append(lines[:maxPinnedLines-1:maxPinnedLines-1], fmt.Sprintf("… %d more line(s)", more))
+	}
+	return lines
+}
+
+// pinnedHeight is the number of rows the pinned region takes, including
+// its title and separator.
+func (m model) pinnedHeight() int {
+	if m.pinned == "" {
+		return 0
+	}
+	return len(m.pinnedLines()) + 2
+}
+
+func (m model) renderPinned(width int) string {
+	var b strings.Builder
+	title := "Pinned"
+	if m.pinnedFrom != "" {
+		title += " · " + m.pinnedFrom
+	}
+	b.WriteString(m.styles.defaultMark.Render(ansi.Truncate(title, width, "…")))
+	b.WriteString("\n")
+	for _, line := range m.pinnedLines() {
+		b.WriteString(ansi.Truncate(line, width, "…"))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.styles.dim.Render(strings.Repeat("─", max(1, width))))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pingTimeRe matches one round-trip time, e.g. "time=12.3 ms",
+// "Round-trip time: 45ms" or "latency 8 ms".
+var pingTimeRe = regexp.MustCompile(`(?i)(?:time|rtt|latency)\s*[=:<]?\s*(\d+(?:\.\d+)?)\s*ms\b`)
+
+// parsePingTimes collects round-trip times in milliseconds, skipping
+// summary lines so their figures are not counted twice.
+func parsePingTimes(lines []string) []float64 {
+	var times []float64
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "min") && strings.Contains(lower, "max") {
+			continue
+		}
+		if m := pingTimeRe.FindStringSubmatch(line); m != nil {
+			if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+				times = append(times, v)
+			}
+		}
+	}
+	return times
+}
+
+// renderPingStats summarizes round-trip times as a one-line panel, or
+// returns "" when there are none.
+func (s styles) renderPingStats(times []float64) string {
+	if len(times) == 0 {
+		return ""
+	}
+	lo, hi, sum := times[0], times[0], 0.0
+	for _, t := range times {
+		if t < lo {
+			lo = t
+		}
+		if t > hi {
+			hi = t
+		}
+		sum += t
+	}
+	return s.paneTitle.Render(fmt.Sprintf("%d replies", len(times))) +
+		fmt.Sprintf("  min %.1f ms  avg %.1f ms  max %.1f ms", lo, sum/float64(len(times)), hi)
+}
+
+// withPingStats puts the stats panel above ping output when it has times.
+func (s styles) withPingStats(output string) string {
+	if stats := s.renderPingStats(parsePingTimes(strings.Split(output, "\n"))); stats != "" {
+		return stats + "\n\n" + output
+	}
+	return output
+}
+
+// validateURI accepts absolute http(s) URLs such as a tunnel's web address.
+func validateURI(v string) error {
+	u, err := url.Parse(v)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("expected an http:// or https:// URL")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type pollTickMsg struct{}
+
+type tunnelCountMsg struct {
+	count int
+	err   error
+}
+
+var tunnelCountRe = regexp.MustCompile(`(?i)found\s+(\d+)\s+tunnel`)
+
+// parseTunnelCount reads the "Found N tunnels" summary printed by `list`.
+func parseTunnelCount(output string) (int, bool) {
+	m := tunnelCountRe.FindStringSubmatch(output)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+func (m model) pollTickCmd() tea.Cmd {
+	if m.cfg.PollSeconds <= 0 {
+		return nil
+	}
+	return tea.Tick(time.Duration(m.cfg.PollSeconds)*time.Second, func(time.Time) tea.Msg {
+		return pollTickMsg{}
+	})
+}
+
+// pollTunnelsCmd refreshes the tunnel count in the background without
+// touching the output pane.
+func (m model) pollTunnelsCmd() tea.Cmd {
+	procs := m.procs
+	return func() tea.Msg {
+		out, err := runQuiet(procs, "devtunnel", "list")
+		if err != nil {
+			return tunnelCountMsg{err: err}
+		}
+		n, ok := parseTunnelCount(out)
+		if !ok {
+			return nil
+		}
+		return tunnelCountMsg{count: n}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prefetchIdle is how long the app must go without input or a running
+// command before cached results are refreshed in the background.
+const prefetchIdle = 10 * time.Second
+
+// prefetchTargets are cheap read-only commands worth having cached.
+var prefetchTargets = [][]string{
+	{"devtunnel", "limits"},
+	{"devtunnel", "clusters"},
+}
+
+type prefetchTickMsg struct{}
+
+// prefetchMsg carries background results. gen ties them to the prefetch
+// that produced them, so results of a cancelled one are dropped.
+type prefetchMsg struct {
+	gen     int
+	results map[string]cacheEntry
+}
+
+func (m model) prefetchTickCmd() tea.Cmd {
+	if !m.cfg.Prefetch || m.cfg.CacheTTLSeconds <= 0 {
+		return nil
+	}
+	return tea.Tick(prefetchIdle, func(time.Time) tea.Msg { return prefetchTickMsg{} })
+}
+
+// staleTargets returns the prefetch commands, as the catalog would run
+// them, whose cache entry is missing or past half its TTL, so a refreshed
+// entry is still fresh when the user gets to it.
+func (m model) staleTargets() [][]string {
+	ttl := time.Duration(m.cfg.CacheTTLSeconds) * time.Second
+	var out [][]string
+	for _, t := range prefetchTargets {
+		cmd := m.lookupCommand(t)
+		if cmd == nil || !cmd.cacheable {
+			continue
+		}
+		parts := m.withCluster(cmd, append([]string(nil), t...))
+		if entry, ok := m.cache[cacheKey(parts)]; ok && time.Since(entry.at) < ttl/2 {
+			continue
+		}
+		out = append(out, parts)
+	}
+	return out
+}
+
+// onPrefetchTick starts a background refresh when the app has been idle,
+// and schedules the next check.
+func (m model) onPrefetchTick() (tea.Model, tea.Cmd) {
+	next := m.prefetchTickCmd()
+	if m.running || m.prefetchCancel != nil || time.Since(m.lastInput) < prefetchIdle {
+		return m, next
+	}
+	targets := m.staleTargets()
+	if len(targets) == 0 {
+		return m, next
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.prefetchGen++
+	m.prefetchCancel = cancel
+	return m, tea.Batch(next, m.fetchLoginCmd(), prefetchCmd(ctx, m.procs, m.workDir, m.prefetchGen, targets))
+}
+
+// cancelPrefetch stops a background refresh, e.g. when a foreground
+// command starts.
+func (m model) cancelPrefetch() model {
+	if m.prefetchCancel != nil {
+		m.prefetchCancel()
+		m.prefetchCancel = nil
+		m.prefetchGen++
+	}
+	return m
+}
+
+func (m model) onPrefetched(msg prefetchMsg) model {
+	if msg.gen != m.prefetchGen {
+		return m
+	}
+	m.prefetchCancel = nil
+	for k, e := range msg.results {
+		m.cache[k] = e
+	}
+	return m
+}
+
+// prefetchCmd runs targets one after another, keeping successful results.
+func prefetchCmd(ctx context.Context, procs *procTable, dir string, gen int, targets [][]string) tea.Cmd {
+	return func() tea.Msg {
+		results := map[string]cacheEntry{}
+		for _, parts := range targets {
+			if ctx.Err() != nil {
+				break
+			}
+			if e, err := runCapture(ctx, procs, dir, parts); err == nil {
+				results[cacheKey(parts)] = e
+			}
+		}
+		return prefetchMsg{gen: gen, results: results}
+	}
+}
+
+// runCapture runs parts quietly, keeping the combined output and each
+// stream separately.
+func runCapture(parent context.Context, procs *procTable, dir string, parts []string) (cacheEntry, error) {
+	ctx, cancel := context.WithTimeout(parent, 30*time.Second)
+	defer cancel()
+	ctx, release := procs.track(ctx)
+	defer release()
+
+	cmd := exec.CommandContext(ctx, procs.program(parts[0]), parts[1:]...)
+	cmd.Dir = dir
+	cmd.WaitDelay = 2 * time.Second
+	var combined, stdout, stderr bytes.Buffer
+	cmd.Stdout = io.MultiWriter(&combined, &stdout)
+	cmd.Stderr = io.MultiWriter(&combined, &stderr)
+	if err := cmd.Run(); err != nil {
+		return cacheEntry{}, err
+	}
+	return cacheEntry{output: combined.String(), stdout: stdout.String(), stderr: stderr.String(), at: time.Now()}, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// procTable tracks the contexts of running child commands so they can be
+// cancelled and reaped when the TUI exits.
+type procTable struct {
+	bin     string // devtunnel binary to run, "" to find it on PATH
+	mu      sync.Mutex
+	nextID  int
+	closed  bool
+	cancels map[int]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newProcTable(bin string) *procTable {
+	return &procTable{bin: bin, cancels: map[int]context.CancelFunc{}}
+}
+
+// program returns the executable to start for name, which is the
+// configured binary for devtunnel.
+func (p *procTable) program(name string) string {
+	if name == "devtunnel" && p.bin != "" {
+		return p.bin
+	}
+	return name
+}
+
+// track derives a cancellable context for a child command. release must be
+// called once the command has been waited on.
+func (p *procTable) track(parent context.Context) (ctx context.Context, release func()) {
+	ctx, cancel := context.WithCancel(parent)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		cancel()
+		return ctx, func() {}
+	}
+	id := p.nextID
+	p.nextID++
+	p.cancels[id] = cancel
+	p.wg.Add(1)
+	return ctx, func() {
+		p.mu.Lock()
+		delete(p.cancels, id)
+		p.mu.Unlock()
+		cancel()
+		p.wg.Done()
+	}
+}
+
+// shutdown cancels every tracked command and waits up to timeout for them
+// to be reaped. Commands started afterwards are cancelled immediately.
+func (p *procTable) shutdown(timeout time.Duration) {
+	p.mu.Lock()
+	p.closed = true
+	for _, cancel := range p.cancels {
+		cancel()
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// runQuiet runs a short helper command in the background without touching
+// the output pane, returning its combined output.
+func runQuiet(procs *procTable, parts ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ctx, release := procs.track(ctx)
+	defer release()
+
+	cmd := exec.CommandContext(ctx, procs.program(parts[0]), parts[1:]...)
+	cmd.WaitDelay = 2 * time.Second
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
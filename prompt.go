@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind identifies what a one-line prompt collects, so submitPrompt
+// knows where the value goes.
+type promptKind int
+
+const (
+	promptWorkDir promptKind = iota
+	promptWatch
+	promptBookmark
+	promptSearch
+)
+
+func newPromptInput() textinput.Model {
+	in := textinput.New()
+	in.CharLimit = 500
+	return in
+}
+
+// openPrompt shows a one-line input in the bottom bar, prefilled with value.
+func (m model) openPrompt(kind promptKind, label, value string) (model, tea.Cmd) {
+	m.promptMode = true
+	m.promptKind = kind
+	m.promptInput.Prompt = label + ": "
+	m = m.resizeInputs()
+	m.promptInput.SetValue(value)
+	m.promptInput.CursorEnd()
+	m.promptInput.Focus()
+	return m, textinput.Blink
+}
+
+func (m model) updatePrompt(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch k.String() {
+	case "esc":
+		m.promptMode = false
+		m.promptInput.Blur()
+		return m, nil
+	case "enter":
+		m.promptMode = false
+		m.promptInput.Blur()
+		return m.submitPrompt(m.promptKind, m.promptInput.Value())
+	}
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(k)
+	return m, cmd
+}
+
+func (m model) submitPrompt(kind promptKind, value string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case promptWorkDir:
+		return m.setWorkDir(value), nil
+	case promptWatch:
+		return m.startWatch(value)
+	case promptBookmark:
+		return m.addBookmark(value), nil
+	case promptSearch:
+		return m.searchOutput(value), nil
+	}
+	return m, nil
+}
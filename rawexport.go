@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// rawEncodings are the choices of the X picker. Both wrap lines and can be
+// decoded back to the exact bytes with standard tools.
+var rawEncodings = []struct {
+	name, ext, decode string
+	encode            func([]byte) string
+	width             int
+}{
+	{"base64", "b64", "base64 -d", base64.StdEncoding.EncodeToString, 76},
+	{"hex", "hex", "xxd -r -p", hex.EncodeToString, 64},
+}
+
+// openRawExport asks for the encoding to export the latest result with.
+func (m model) openRawExport() model {
+	if m.lastText == "" || m.running {
+		m.statusErr = true
+		m.statusText = "no finished command to export"
+		return m
+	}
+	items := make([]string, len(rawEncodings))
+	for i, e := range rawEncodings {
+		items[i] = e.name + "  (decode with " + e.decode + ")"
+	}
+	return m.openPicker(pickEncoding, "Export raw "+m.outStream.String()+" output as", items, 0)
+}
+
+// exportRaw writes the exact bytes of the latest result in the shown
+// stream, before any sanitizing for display, encoded to a file in the
+// working directory.
+func (m model) exportRaw(idx int) model {
+	enc := rawEncodings[idx]
+	text := enc.encode([]byte(m.resultBody()))
+	var b strings.Builder
+	for len(text) > enc.width {
+		b.WriteString(text[:enc.width] + "\n")
+		text = text[enc.width:]
+	}
+	b.WriteString(text + "\n")
+	path, err := filepath.Abs("devtunnel-tui-output-" + time.Now().Format("20060102-150405") + "." + enc.ext)
+	if err == nil {
+		err = os.WriteFile(path, []byte(b.String()), 0o600)
+	}
+	if err != nil {
+		m.statusErr = true
+		m.statusText = "could not export output: " + err.Error()
+		return m
+	}
+	m.statusErr = false
+	m.statusText = "raw output written as " + enc.name + " to " + path
+	return m
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rerunBase is the result a diffing rerun is compared with.
+type rerunBase struct {
+	cmdText string
+	output  string
+}
+
+// rerunWithDiff runs the last command again and shows what changed in its
+// output. Without a previous result for it, the new output shows in full.
+func (m model) rerunWithDiff() (tea.Model, tea.Cmd) {
+	if len(m.lastCmd) == 0 {
+		m.statusErr = true
+		m.statusText = "no command to rerun"
+		return m, nil
+	}
+	m.rerunBase = nil
+	if text := m.secrets.displayCmd(m.lastCmd); m.lastText == text {
+		m.rerunBase = &rerunBase{cmdText: text, output: m.lastOutput}
+	}
+	return m.rerun(m.lastCmd)
+}
+
+// showRerunDiff replaces the finished result with its diff against base.
+func (m model) showRerunDiff(base *rerunBase, output string) model {
+	body, added, removed := m.styles.renderDiff(diffLines(splitLines(base.output), splitLines(output)))
+	if added == 0 && removed == 0 {
+		m.statusText += "; no changes since the previous run"
+		return m
+	}
+	m = m.setOutput(fmt.Sprintf("diff  --- %s (previous run)\n      +++ %s (this run)\n\n%s", base.cmdText, base.cmdText, body))
+	m.viewport.GotoTop()
+	m.statusText += fmt.Sprintf("; changes: +%d -%d", added, removed)
+	return m
+}
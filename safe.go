@@ -0,0 +1,115 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// safeModeBlockedMsg reports a command that safe mode refused to start.
+type safeModeBlockedMsg struct {
+	cmdText string
+}
+
+// destructiveSubverbs are destructive subcommands reached through the
+// generic port and access entries or command mode, which have no catalog
+// entry of their own to mark.
+var destructiveSubverbs = [][]string{
+	{"port", "delete"},
+	{"access", "delete"},
+	{"access", "reset"},
+}
+
+// valueFlags take the next argument as their value, so it is not mistaken
+// for a subcommand word.
+var valueFlags = map[string]bool{
+	clusterFlag: true,
+}
+
+// destructiveArgs lists the destructive subcommands: those of catalog
+// entries marked destructive, e.g. ["delete-all"], and destructiveSubverbs.
+func destructiveArgs() [][]string {
+	out := slices.Clone(destructiveSubverbs)
+	for _, cat := range catalog() {
+		for _, c := range cat.commands {
+			if c.destructive {
+				out = append(out, c.baseArgs)
+			}
+		}
+	}
+	return out
+}
+
+// refusedInSafeMode reports whether parts would run a destructive devtunnel
+// subcommand. Shell commands are refused as a whole since their contents
+// cannot be checked.
+func refusedInSafeMode(parts []string) bool {
+	if len(parts) == 0 {
+		return false
+	}
+	if parts[0] == "sh" {
+		return true
+	}
+	if parts[0] != "devtunnel" {
+		return false
+	}
+	words := subcommandWords(parts[1:])
+	for _, base := range destructiveArgs() {
+		if len(words) >= len(base) && slices.Equal(words[:len(base)], base) {
+			return true
+		}
+	}
+	return false
+}
+
+// subcommandWords returns args without flags and their values, leaving the
+// subcommand words and positional arguments in order.
+func subcommandWords(args []string) []string {
+	var words []string
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case valueFlags[a]:
+			i++
+		case strings.HasPrefix(a, "-"):
+		default:
+			words = append(words, a)
+		}
+	}
+	return words
+}
+
+// safeModeRefusal returns the command that reports parts as blocked.
+func safeModeRefusal(cmdText string) tea.Cmd {
+	return func() tea.Msg { return safeModeBlockedMsg{cmdText: cmdText} }
+}
+
+// onSafeModeBlocked reports a refused command, ending any workflow or
+// pasted sequence it belonged to.
+func (m model) onSafeModeBlocked(msg safeModeBlockedMsg) model {
+	if m.workflow != nil {
+		m.running = false
+		m.workflow = nil
+	}
+	m.nextBackground = false
+	m.statusErr = true
+	m.statusText = "safe mode: refused " + msg.cmdText
+	return m
+}
+
+// toggleSafeMode switches safe mode for the session. When the config turns
+// it on it stays on, so a shared setup cannot be unlocked from the keyboard.
+func (m model) toggleSafeMode() model {
+	if m.cfg.SafeMode {
+		m.statusErr = true
+		m.statusText = "safe mode is set in config"
+		return m
+	}
+	m.safeMode = !m.safeMode
+	m.statusErr = false
+	m.statusText = "safe mode off"
+	if m.safeMode {
+		m.statusText = "safe mode on: destructive commands are locked"
+	}
+	return m
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestRefusedInSafeMode(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []string
+		want  bool
+	}{
+		{"delete", []string{"devtunnel", "delete", "my-tunnel"}, true},
+		{"delete-all", []string{"devtunnel", "delete-all"}, true},
+		{"unset", []string{"devtunnel", "unset"}, true},
+		{"flag before subcommand", []string{"devtunnel", "--verbose", "delete", "my-tunnel"}, true},
+		{"flag with value before subcommand", []string{"devtunnel", "--cluster", "usw2", "delete", "my-tunnel"}, true},
+		{"port delete", []string{"devtunnel", "port", "delete", "my-tunnel", "-p", "8080"}, true},
+		{"port delete after flag", []string{"devtunnel", "port", "--verbose", "delete", "my-tunnel"}, true},
+		{"access delete", []string{"devtunnel", "access", "delete", "my-tunnel"}, true},
+		{"access reset", []string{"devtunnel", "access", "reset", "my-tunnel"}, true},
+		{"user logout", []string{"devtunnel", "user", "logout"}, true},
+		{"shell", []string{"sh", "-c", "ls"}, true},
+		{"list", []string{"devtunnel", "list", "--all"}, false},
+		{"port list", []string{"devtunnel", "port", "list", "my-tunnel"}, false},
+		{"access list", []string{"devtunnel", "access", "list", "my-tunnel"}, false},
+		{"tunnel named delete", []string{"devtunnel", "show", "delete"}, false},
+		{"user show", []string{"devtunnel", "user", "show"}, false},
+		{"other program", []string{"echo", "delete"}, false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refusedInSafeMode(tt.parts); got != tt.want {
+				t.Errorf("refusedInSafeMode(%q) = %v, want %v", tt.parts, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// sanitizeOutput makes text safe to hand to the viewport: invalid UTF-8
+// becomes U+FFFD, and control characters and escape sequences are dropped,
+// except newlines, tabs and SGR color sequences (ESC [ ... m) such as the
+// ones our own highlighting adds.
+func sanitizeOutput(s string) string {
+	if isPlainText(s) {
+		return s
+	}
+	s = strings.ToValidUTF8(s, "�")
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\x1b':
+			end, keep := scanEscape(s, i)
+			if keep {
+				b.WriteString(s[i:end])
+			}
+			i = end
+			continue
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0):
+			// other C0/C1 controls, including stray \r and bells
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isPlainText reports whether s is valid UTF-8 without any characters
+// sanitizeOutput would touch, so the common case skips the copy.
+func isPlainText(s string) bool {
+	for _, r := range s {
+		if r == utf8.RuneError || (r < 0x20 && r != '\n' && r != '\t') || (r >= 0x7f && r < 0xa0) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanEscape returns the index just past the escape sequence starting at
+// s[start], and whether it is an SGR sequence worth keeping.
+func scanEscape(s string, start int) (end int, keep bool) {
+	i := start + 1
+	if i >= len(s) {
+		return i, false
+	}
+	switch s[i] {
+	case '[': // CSI: parameters, then a final byte in 0x40-0x7e
+		for i++; i < len(s); i++ {
+			c := s[i]
+			if c >= 0x40 && c <= 0x7e {
+				return i + 1, c == 'm' && strings.Trim(s[start+2:i], "0123456789;:") == ""
+			}
+			if c < 0x20 || c > 0x7e {
+				return i, false
+			}
+		}
+		return len(s), false
+	case ']', 'P', '_', '^': // OSC/DCS/APC/PM: up to BEL or ST
+		for i++; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1, false
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2, false
+			}
+		}
+		return len(s), false
+	default: // intermediates (e.g. the "(" of ESC ( B), then a final byte
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i >= len(s) {
+			return i, false
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		return i + size, false
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// findMatches records the output lines containing the search query,
+// ignoring case and styling. The current match is reset since line numbers
+// may have changed.
+func (m model) findMatches() model {
+	m.matches = nil
+	m.matchIdx = -1
+	if m.searchQuery == "" {
+		return m
+	}
+	q := strings.ToLower(m.searchQuery)
+	for i, line := range m.outLines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), q) {
+			m.matches = append(m.matches, i)
+		}
+	}
+	return m
+}
+
+// searchOutput sets the query and jumps to the first match at or below the
+// cursor, wrapping to the top. An empty query clears the search.
+func (m model) searchOutput(query string) model {
+	m.searchQuery = strings.TrimSpace(query)
+	m = m.findMatches()
+	if m.searchQuery == "" {
+		m.statusErr = false
+		m.statusText = "search cleared"
+		return m
+	}
+	if len(m.matches) == 0 {
+		m.statusErr = true
+		m.statusText = fmt.Sprintf("no matches for %q", m.searchQuery)
+		return m
+	}
+	m.matchIdx = 0
+	for i, line := range m.matches {
+		if line >= m.outCursor {
+			m.matchIdx = i
+			break
+		}
+	}
+	return m.showMatch()
+}
+
+// stepMatch moves to the next (delta 1) or previous (delta -1) match,
+// wrapping around.
+func (m model) stepMatch(delta int) model {
+	if len(m.matches) == 0 {
+		m.statusErr = true
+		m.statusText = fmt.Sprintf("no matches for %q", m.searchQuery)
+		return m
+	}
+	if m.matchIdx < 0 {
+		m.matchIdx = 0
+		if delta < 0 {
+			m.matchIdx = len(m.matches) - 1
+		}
+	} else {
+		m.matchIdx = (m.matchIdx + delta + len(m.matches)) % len(m.matches)
+	}
+	return m.showMatch()
+}
+
+func (m model) showMatch() model {
+	m = m.setCursor(m.matches[m.matchIdx])
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("%q: match %d/%d (n/N for next/previous)", m.searchQuery, m.matchIdx+1, len(m.matches))
+	return m
+}
+
+// matchTitle describes the search position for the output pane title.
+func (m model) matchTitle() string {
+	switch {
+	case m.searchQuery == "":
+		return ""
+	case m.matchIdx < 0:
+		return fmt.Sprintf(" %d matches", len(m.matches))
+	}
+	return fmt.Sprintf(" match %d/%d", m.matchIdx+1, len(m.matches))
+}
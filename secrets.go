@@ -0,0 +1,70 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redacted = "****"
+
+// sensitiveLabelRe matches form labels whose values are treated as secrets
+// even when the command does not list them explicitly.
+var sensitiveLabelRe = regexp.MustCompile(`(?i)token|secret|password|api-?key`)
+
+// sensitiveFlags take a secret value, either as the next argument or
+// after "=".
+var sensitiveFlags = map[string]bool{
+	"--access-token": true,
+	"--token":        true,
+	"--password":     true,
+	"--secret":       true,
+}
+
+// secretSet holds values entered into sensitive fields this session so they
+// can be scrubbed from anything displayed or recorded.
+type secretSet map[string]bool
+
+func (c commandItem) isSensitive(label string) bool {
+	for _, s := range c.sensitive {
+		if s == label {
+			return true
+		}
+	}
+	return sensitiveLabelRe.MatchString(label)
+}
+
+// with returns a copy of s that also holds values, leaving s untouched.
+func (s secretSet) with(values ...string) secretSet {
+	out := make(secretSet, len(s)+len(values))
+	for v := range s {
+		out[v] = true
+	}
+	for _, v := range values {
+		out[v] = true
+	}
+	return out
+}
+
+// redactArgs returns a copy of parts safe to display or record: known
+// secret values and the values of sensitive flags are masked.
+func (s secretSet) redactArgs(parts []string) []string {
+	out := make([]string, len(parts))
+	maskNext := false
+	for i, p := range parts {
+		switch {
+		case maskNext, s[p]:
+			out[i] = redacted
+		default:
+			out[i] = p
+			if name, _, ok := strings.Cut(p, "="); ok && sensitiveFlags[name] {
+				out[i] = name + "=" + redacted
+			}
+		}
+		maskNext = sensitiveFlags[p]
+	}
+	return out
+}
+
+func (s secretSet) displayCmd(parts []string) string {
+	return strings.Join(s.redactArgs(parts), " ")
+}
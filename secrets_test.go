@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func TestInspectArgsRedactsFormSecrets(t *testing.T) {
+	cmd, err := customCommand(customCommandConfig{
+		Name:     "show with token",
+		BaseArgs: []string{"user show {tunnel-id} {access-token}"},
+		Required: []string{"api-token", "region"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{
+		"tunnel-id":    "my-tunnel",
+		"access-token": "param-secret",
+		"api-token":    "field-secret",
+		"region":       "usw2",
+	}
+	m := model{secrets: secretSet{}, formMode: true, formCmd: &cmd, formLabels: cmd.required}
+	for _, name := range cmd.required {
+		ti := textinput.New()
+		ti.SetValue(values[name])
+		m.formInputs = append(m.formInputs, ti)
+		m.formChecks = append(m.formChecks, checklist{})
+	}
+
+	got := m.inspectArgs(cmd)
+	want := []string{"devtunnel", "user", "show", "my-tunnel", redacted, redacted, "usw2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inspectArgs() = %q, want %q", got, want)
+	}
+	if len(m.secrets) != 0 {
+		t.Errorf("inspectArgs changed the session secrets: %v", m.secrets)
+	}
+}
+
+func TestCustomCommandSensitive(t *testing.T) {
+	cmd, err := customCommand(customCommandConfig{
+		Name:      "signed show",
+		BaseArgs:  []string{"show {tunnel-id}"},
+		Required:  []string{"signing-key"},
+		Sensitive: []string{"signing-key"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmd.isSensitive("signing-key") || cmd.isSensitive("tunnel-id") {
+		t.Errorf("isSensitive: signing-key %v, tunnel-id %v", cmd.isSensitive("signing-key"), cmd.isSensitive("tunnel-id"))
+	}
+	if _, err := customCommand(customCommandConfig{Name: "bad", BaseArgs: []string{"show"}, Sensitive: []string{"nope"}}); err == nil {
+		t.Error("sensitive name without a matching field was accepted")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// logEntry is one finished command in the session log.
+type logEntry struct {
+	cmdText string
+	args    []string
+	start   time.Time
+	end     time.Time
+	output  string
+	err     error
+}
+
+// exitStatus renders the entry's result as an exit code where there is one.
+func (e logEntry) exitStatus() string {
+	var exitErr *exec.ExitError
+	switch {
+	case e.err == nil:
+		return "exit 0"
+	case errors.As(e.err, &exitErr):
+		return fmt.Sprintf("exit %d", exitErr.ExitCode())
+	default:
+		return "error: " + e.err.Error()
+	}
+}
+
+// appendSessionLog records a finished command, dropping the oldest entries
+// beyond historySize.
+func (m model) appendSessionLog(e logEntry) model {
+	m.sessionLog = append(m.sessionLog, e)
+	if n := m.cfg.HistorySize; n > 0 && len(m.sessionLog) > n {
+		m.sessionLog = m.sessionLog[len(m.sessionLog)-n:]
+	}
+	return m
+}
+
+// formatSessionLog renders every entry with a separator between them.
+func formatSessionLog(entries []logEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "devtunnel-tui session log, %d command(s)\n", len(entries))
+	for _, e := range entries {
+		b.WriteString("\n" + strings.Repeat("=", 72) + "\n")
+		fmt.Fprintf(&b, "$ %s\n", e.cmdText)
+		fmt.Fprintf(&b, "started:  %s\n", e.start.Format(time.RFC3339))
+		fmt.Fprintf(&b, "finished: %s (%s)\n", e.end.Format(time.RFC3339), e.end.Sub(e.start).Round(time.Millisecond))
+		fmt.Fprintf(&b, "result:   %s\n", e.exitStatus())
+		b.WriteString(strings.Repeat("-", 72) + "\n")
+		b.WriteString(strings.TrimRight(e.output, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// exportSessionLog writes the session log to a timestamped file in the
+// working directory and returns its absolute path.
+func exportSessionLog(entries []logEntry) (string, error) {
+	name := "devtunnel-tui-session-" + time.Now().Format("20060102-150405") + ".log"
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(formatSessionLog(entries)), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import "strings"
+
+// shellQuote quotes s for a POSIX shell. Words made only of safe
+// characters are left bare; anything else is single-quoted.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,@+%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin renders argv as one shell command line.
+func shellJoin(parts []string) string {
+	quoted := make([]string, len(parts))
+	for i, p := range parts {
+		quoted[i] = shellQuote(p)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// formSnippet renders the open form as shell lines without running it:
+// one line for a command, one per step for a workflow.
+func (m model) formSnippet() string {
+	args, params, _ := m.formArgList()
+	if wf := m.formCmd.workflow; wf != nil {
+		steps := expandWorkflow(wf, params)
+		lines := make([]string, len(steps))
+		for i, step := range steps {
+			lines[i] = shellJoin(step)
+		}
+		return strings.Join(lines, "\n")
+	}
+	return shellJoin(argValues(args))
+}
+
+// splitArgs splits free text into arguments the way a POSIX shell would
+// for plain words: whitespace separates, single quotes are literal, double
+// quotes honor \" \\ \$ and \`, and a backslash outside quotes escapes the
+// next character. An unterminated quote runs to the end of the input.
+func splitArgs(s string) []string {
+	var (
+		args  []string
+		cur   strings.Builder
+		inArg bool
+	)
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		case r == '\\':
+			inArg = true
+			if i+1 < len(rs) {
+				i++
+				cur.WriteRune(rs[i])
+			}
+		case r == '\'':
+			inArg = true
+			for i++; i < len(rs) && rs[i] != '\''; i++ {
+				cur.WriteRune(rs[i])
+			}
+		case r == '"':
+			inArg = true
+			for i++; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) && strings.ContainsRune("\"\\$`", rs[i+1]) {
+					i++
+				}
+				cur.WriteRune(rs[i])
+			}
+		default:
+			inArg = true
+			cur.WriteRune(r)
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args
+}
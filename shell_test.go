@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"blank", "  \t ", nil},
+		{"plain words", "--all  --json", []string{"--all", "--json"}},
+		{"double quoted value", `--description "my tunnel"`, []string{"--description", "my tunnel"}},
+		{"single quoted value", `--description 'my "best" tunnel'`, []string{"--description", `my "best" tunnel`}},
+		{"escaped space", `--description my\ tunnel`, []string{"--description", "my tunnel"}},
+		{"escaped quote in double quotes", `"say \"hi\""`, []string{`say "hi"`}},
+		{"backslash kept in double quotes", `"C:\path"`, []string{`C:\path`}},
+		{"quotes inside a word", `--label=team"a b"`, []string{"--label=team" + "a b"}},
+		{"empty quoted argument", `--description ""`, []string{"--description", ""}},
+		{"mixed flags", `-p 8080 --description "web app" --allow-anonymous -e 'in 1h'`,
+			[]string{"-p", "8080", "--description", "web app", "--allow-anonymous", "-e", "in 1h"}},
+		{"unterminated quote runs to end", `--description "my tunnel`, []string{"--description", "my tunnel"}},
+		{"trailing backslash", `abc\`, []string{"abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitArgs(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellJoinRoundTrip(t *testing.T) {
+	args := []string{"devtunnel", "create", "--description", "it's a \"tunnel\"", "", "a\\b", "$HOME"}
+	if got := splitArgs(shellJoin(args)); !reflect.DeepEqual(got, args) {
+		t.Errorf("splitArgs(shellJoin(%q)) = %q", args, got)
+	}
+}
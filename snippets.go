@@ -0,0 +1,43 @@
+package main
+
+import "strings"
+
+// flagSnippets are inserted into the command line by Alt+key in command
+// mode. Snippets ending in a space expect a value after them.
+var flagSnippets = map[string]string{
+	"alt+j": "--json",
+	"alt+a": "--allow-anonymous",
+	"alt+p": "-p ",
+	"alt+l": "--labels ",
+	"alt+d": "--description ",
+	"alt+e": "--expiration ",
+	"alt+c": "--cluster ",
+	"alt+v": "--verbose",
+}
+
+// insertSnippet puts snippet at the cursor, adding a space on either side
+// where it would otherwise run into neighbouring text.
+func insertSnippet(value string, pos int, snippet string) (string, int) {
+	rs := []rune(value)
+	pos = max(0, min(pos, len(rs)))
+	before, after := string(rs[:pos]), string(rs[pos:])
+	if before != "" && !strings.HasSuffix(before, " ") {
+		snippet = " " + snippet
+	}
+	if after != "" && !strings.HasPrefix(after, " ") && !strings.HasSuffix(snippet, " ") {
+		snippet += " "
+	}
+	return before + snippet + after, pos + len([]rune(snippet))
+}
+
+// insertFlagSnippet handles an Alt+key snippet in command mode.
+func (m model) insertFlagSnippet(key string) (model, bool) {
+	snippet, ok := flagSnippets[key]
+	if !ok {
+		return m, false
+	}
+	value, pos := insertSnippet(m.cmdInput.Value(), m.cmdInput.Position(), snippet)
+	m.cmdInput.SetValue(value)
+	m.cmdInput.SetCursor(pos)
+	return m, true
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// spinnerStyles are the bubbles spinners selectable with spinner.style.
+var spinnerStyles = map[string]spinner.Spinner{
+	"line":      spinner.Line,
+	"dot":       spinner.Dot,
+	"minidot":   spinner.MiniDot,
+	"jump":      spinner.Jump,
+	"pulse":     spinner.Pulse,
+	"points":    spinner.Points,
+	"globe":     spinner.Globe,
+	"moon":      spinner.Moon,
+	"monkey":    spinner.Monkey,
+	"meter":     spinner.Meter,
+	"hamburger": spinner.Hamburger,
+	"ellipsis":  spinner.Ellipsis,
+}
+
+// configSpinner returns the configured spinner, falling back to the dot
+// style for an unknown name. A positive interval replaces the style's own
+// frame rate.
+func configSpinner(sc spinnerConfig) (spinner.Spinner, []string) {
+	var warnings []string
+	s := spinner.Dot
+	if sc.Style != "" {
+		if st, ok := spinnerStyles[strings.ToLower(sc.Style)]; ok {
+			s = st
+		} else {
+			names := make([]string, 0, len(spinnerStyles))
+			for name := range spinnerStyles {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			warnings = append(warnings, fmt.Sprintf("spinner.style: unknown style %q (using dot; one of %s)", sc.Style, strings.Join(names, ", ")))
+		}
+	}
+	if sc.IntervalMS > 0 {
+		s.FPS = time.Duration(sc.IntervalMS) * time.Millisecond
+	}
+	return s, warnings
+}
+
+// spinnerTick starts the spinner animation, or nothing in reduced-motion
+// mode, where no ticks are scheduled at all.
+func (m model) spinnerTick() tea.Cmd {
+	if m.cfg.ReducedMotion {
+		return nil
+	}
+	return m.spinner.Tick
+}
+
+// spinnerView is the running indicator: the spinner, or a static label in
+// reduced-motion mode.
+func (m model) spinnerView() string {
+	if m.cfg.ReducedMotion {
+		return m.spinner.Style.Render("running...")
+	}
+	return m.spinner.View()
+}
+
+// noAnimation reports whether the NO_ANIMATION convention asks for reduced
+// motion: set to anything but empty or 0.
+func noAnimation(lookup func(string) (string, bool)) bool {
+	v, ok := lookup("NO_ANIMATION")
+	return ok && v != "" && v != "0"
+}
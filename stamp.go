@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stampedLine is a line of streamed output with its arrival time.
+type stampedLine struct {
+	at   time.Time
+	text string
+}
+
+const stampLayout = "15:04:05.000"
+
+// renderStreamed lays out streamed lines under header, prefixing arrival
+// times when timestamps are on. It also returns the lines without stamps
+// so copying picks up only the command's own text.
+func (m model) renderStreamed(header string, lines []stampedLine) (display string, raw []string) {
+	if !m.timestamps {
+		var b strings.Builder
+		b.WriteString(header)
+		for _, l := range lines {
+			b.WriteString(l.text)
+			b.WriteString("\n")
+		}
+		return b.String(), nil
+	}
+	raw = strings.Split(header, "\n")
+	raw = raw[:len(raw)-1]
+	shown := append([]string(nil), raw...)
+	for _, l := range lines {
+		shown = append(shown, m.styles.dim.Render(l.at.Format(stampLayout))+" "+l.text)
+		raw = append(raw, l.text)
+	}
+	return strings.Join(shown, "\n") + "\n", append(raw, "")
+}
+
+// showLive redraws the output pane from the lines streamed so far.
+func (m model) showLive() model {
+	header := m.liveHeader
+	if m.runSubcmd == "ping" {
+		texts := make([]string, len(m.liveLines))
+		for i, l := range m.liveLines {
+			texts[i] = l.text
+		}
+		if stats := m.styles.renderPingStats(parsePingTimes(texts)); stats != "" {
+			header += stats + "\n\n"
+		}
+	}
+	display, raw := m.renderStreamed(header, m.liveLines)
+	m = m.setOutput(display)
+	m.outRaw = raw
+	m.viewport.GotoBottom()
+	return m
+}
+
+// liveRedrawInterval bounds how often streamed output redraws the pane, so
+// a chatty command does not re-render its whole buffer for every line.
+const liveRedrawInterval = 100 * time.Millisecond
+
+// liveRedrawMsg redraws the stream the output pane follows.
+type liveRedrawMsg struct{}
+
+// scheduleRedraw asks for a redraw after the interval unless one is
+// already pending.
+func (m model) scheduleRedraw() (model, tea.Cmd) {
+	if m.redrawPending {
+		return m, nil
+	}
+	m.redrawPending = true
+	return m, tea.Tick(liveRedrawInterval, func(time.Time) tea.Msg { return liveRedrawMsg{} })
+}
+
+// onLiveRedraw shows the lines streamed so far by whatever the pane
+// follows: the background job being viewed or the foreground run.
+// Finished runs have already drawn their final output.
+func (m model) onLiveRedraw() model {
+	m.redrawPending = false
+	switch j := m.viewedJob(); {
+	case j != nil:
+		if !j.done {
+			m = m.showJob(j)
+		}
+	case m.running:
+		m = m.showLive()
+	}
+	return m
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// uiState holds preferences toggled from inside the TUI. It lives next to
+// config.json but is owned by the app, so the user's config is never
+// rewritten.
+type uiState struct {
+	SwapPanes   bool `json:"swapPanes"`
+	ShowCommand bool `json:"showCommand"`
+	// HideCategories collapses the categories pane; number keys still work.
+	HideCategories bool `json:"hideCategories"`
+	// RelativeNumbers numbers focused output lines relative to the cursor.
+	RelativeNumbers bool `json:"relativeNumbers"`
+	// GlobalNav keeps navigation keys on the command list whatever has focus.
+	GlobalNav bool     `json:"globalNav"`
+	Favorites []string `json:"favorites"`
+	// CommandOrder maps category name to orderReverse or orderAlpha.
+	CommandOrder map[string]string `json:"commandOrder"`
+	// LastSeenVersion is the build last run, for the what's-new overlay.
+	LastSeenVersion string `json:"lastSeenVersion"`
+}
+
+func statePath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "state.json"), nil
+}
+
+func loadState() (uiState, error) {
+	var st uiState
+	path, err := statePath()
+	if err != nil {
+		return st, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return uiState{}, err
+	}
+	return st, nil
+}
+
+func saveState(st uiState) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runOutputMsg carries one line of output from a running command. ch is
+// the stream it came from so Update can wait for the next message.
+type runOutputMsg struct {
+	line string
+	ch   <-chan tea.Msg
+}
+
+// lineWriter captures command output and emits it line by line. Only the
+// text after the last carriage return of a line is kept, matching what a
+// terminal would show for progress redraws.
+type lineWriter struct {
+	mu      sync.Mutex
+	out     bytes.Buffer
+	partial []byte
+	emit    func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.out.Write(p)
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(lastCarriageSegment(string(w.partial[:i])))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.emit(lastCarriageSegment(string(w.partial)))
+		w.partial = nil
+	}
+}
+
+func lastCarriageSegment(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		return line[i+1:]
+	}
+	return line
+}
+
+// errInterrupted marks a run stopped with Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// streamCommand runs parts in dir (the current directory when empty),
+// sending a runOutputMsg per line and a final runFinishedMsg with the
+// combined output and each stream on its own on ch. Cancelling parent
+// interrupts the command.
+func streamCommand(parent context.Context, procs *procTable, parts []string, dir, cmdText string, timeout time.Duration, ch chan tea.Msg) {
+	ctx, release := procs.track(parent)
+	defer release()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	w := &lineWriter{emit: func(line string) { ch <- runOutputMsg{line: line, ch: ch} }}
+	cmd := exec.CommandContext(ctx, procs.program(parts[0]), parts[1:]...)
+	cmd.Dir = dir
+	// interrupt like a shell's Ctrl-C; WaitDelay kills it if that is ignored
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = 2 * time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = io.MultiWriter(w, &stdout)
+	cmd.Stderr = io.MultiWriter(w, &stderr)
+	err := cmd.Run()
+	w.flush()
+
+	output := w.out.String()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		output += fmt.Sprintf("\n\nTimed out after %s.", timeout)
+		err = ctx.Err()
+	case parent.Err() != nil:
+		output += "\n\nInterrupted."
+		err = errInterrupted
+	}
+	ch <- runFinishedMsg{
+		cmdText: cmdText,
+		args:    parts,
+		output:  output,
+		stdout:  stdout.String(),
+		stderr:  stderr.String(),
+		err:     err,
+		ch:      ch,
+	}
+}
+
+func waitForStream(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg { return <-ch }
+}
+
+var percentRe = regexp.MustCompile(`(\d{1,3}(?:\.\d+)?)\s?%`)
+
+// parseProgress returns the last percentage in line as a 0..1 fraction.
+func parseProgress(line string) (float64, bool) {
+	matches := percentRe.FindAllStringSubmatch(line, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	pct, err := strconv.ParseFloat(matches[len(matches)-1][1], 64)
+	if err != nil || pct > 100 {
+		return 0, false
+	}
+	return pct / 100, true
+}
+
+// renderProgress draws a fixed-width bar for a 0..1 fraction. It is not
+// bubbles' progress.Model: that package imports harmonica for its spring
+// animation, a module this app does not otherwise depend on, and a bar
+// redrawn from each parsed percentage in the status line has nothing to
+// animate. Drawing it here also keeps it in the theme's ok/dim colors.
+func (s styles) renderProgress(frac float64, width int) string {
+	filled := int(frac*float64(width) + 0.5)
+	bar := s.ok.Render(strings.Repeat("█", filled)) + s.dim.Render(strings.Repeat("░", width-filled))
+	return bar + " " + strconv.Itoa(int(frac*100+0.5)) + "%"
+}
+
+// describeFailure turns a run error into a short status line, telling a
+// missing executable apart from a command that ran and exited non-zero.
+// notFound is set when there was nothing to run at all, naming the binary
+// procs actually started.
+func describeFailure(procs *procTable, parts []string, err error) (status string, notFound bool) {
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return procs.program(parts[0]) + ": not installed or not in PATH", true
+	case errors.Is(err, context.DeadlineExceeded):
+		return "command timed out", false
+	case errors.Is(err, errInterrupted):
+		return "command interrupted", false
+	case errors.As(err, &exitErr):
+		// sh reports an unknown program with exit status 127.
+		if parts[0] == "sh" && exitErr.ExitCode() == 127 {
+			return "shell: command not found (exit 127)", true
+		}
+		return fmt.Sprintf("command failed (exit %d)", exitErr.ExitCode()), false
+	default:
+		return "command failed: " + err.Error(), false
+	}
+}
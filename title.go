@@ -0,0 +1,27 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// titleCmd sets the terminal window title to "devtunnel-tui — state", or
+// does nothing when disabled in config. An empty state resets the title.
+func (m model) titleCmd(state string) tea.Cmd {
+	if !m.cfg.SetTitle {
+		return nil
+	}
+	if state == "" {
+		return tea.SetWindowTitle("")
+	}
+	return tea.SetWindowTitle("devtunnel-tui — " + state)
+}
+
+// subcommandName names a run for the title: the devtunnel subcommand, or
+// the program for anything else.
+func subcommandName(args []string) string {
+	if len(args) > 1 && args[0] == "devtunnel" {
+		return args[1]
+	}
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "command"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type defaultTunnelMsg struct {
+	id string
+}
+
+var tunnelIDRe = regexp.MustCompile(`(?im)^\s*Tunnel ID\s*:\s*(\S+)`)
+
+// parseTunnelID pulls the tunnel id out of `devtunnel show` output.
+func parseTunnelID(output string) string {
+	if m := tunnelIDRe.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// fetchDefaultTunnelCmd asks devtunnel for the default tunnel by running
+// `show` without an id. Any failure means no default is set.
+func (m model) fetchDefaultTunnelCmd() tea.Cmd {
+	procs := m.procs
+	return func() tea.Msg {
+		out, err := runQuiet(procs, "devtunnel", "show")
+		if err != nil {
+			return defaultTunnelMsg{}
+		}
+		return defaultTunnelMsg{id: parseTunnelID(out)}
+	}
+}
+
+// highlightDefault marks lines of list output that mention the default
+// tunnel. Ids may be printed without the cluster suffix.
+func (m model) highlightDefault(output string) string {
+	if m.defaultTunnel == "" {
+		return output
+	}
+	short, _, _ := strings.Cut(m.defaultTunnel, ".")
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, short) {
+			lines[i] = m.styles.defaultMark.Render(line + "  ★ default")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// visualRange returns the first and last output line of the visual
+// selection, which runs from where v was pressed to the cursor.
+func (m model) visualRange() (lo, hi int) {
+	lo, hi = m.visualStart, m.outCursor
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return max(0, lo), min(hi, len(m.outLines)-1)
+}
+
+// toggleVisual starts a line selection at the cursor, or drops it.
+func (m model) toggleVisual() model {
+	m.visual = !m.visual
+	m.visualStart = m.outCursor
+	return m
+}
+
+// copyVisual copies the selected lines as plain text and ends the
+// selection.
+func (m model) copyVisual() model {
+	lo, hi := m.visualRange()
+	lines := make([]string, 0, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		lines = append(lines, m.lineText(i))
+	}
+	copyToClipboard(strings.Join(lines, "\n"))
+	m.visual = false
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("copied lines %d-%d (%d lines)", lo+1, hi+1, hi-lo+1)
+	return m
+}
+
+// visualTitle notes the selection size in the output title.
+func (m model) visualTitle() string {
+	if !m.visual {
+		return ""
+	}
+	lo, hi := m.visualRange()
+	return fmt.Sprintf(" VISUAL %d line(s)", hi-lo+1)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchTickMsg triggers the next watch cycle. gen ties it to the watch that
+// scheduled it so ticks from a stopped watch are dropped.
+type watchTickMsg struct{ gen int }
+
+// watchTarget picks what to watch: the selected command when it runs
+// without input, otherwise the last command run.
+func (m model) watchTarget() []string {
+	cmds := m.visibleCommands()
+	if len(cmds) > 0 {
+		cmd := cmds[min(m.cmdIdx, len(cmds)-1)]
+		if len(cmd.required) == 0 && len(cmd.multi) == 0 && cmd.wizard == nil && cmd.workflow == nil && len(cmd.baseArgs) > 0 {
+			return m.withCluster(&cmd, append([]string{"devtunnel"}, cmd.baseArgs...))
+		}
+	}
+	return m.lastCmd
+}
+
+// toggleWatch stops an active watch, or prompts for the interval of a new
+// one.
+func (m model) toggleWatch() (tea.Model, tea.Cmd) {
+	if m.watching {
+		return m.stopWatch(), nil
+	}
+	if !m.devtunnelFound {
+		m.statusErr = true
+		m.statusText = "install devtunnel CLI first"
+		return m, nil
+	}
+	if len(m.watchTarget()) == 0 {
+		m.statusErr = true
+		m.statusText = "nothing to watch: select a command without inputs or run one first"
+		return m, nil
+	}
+	if h := m.promptHandlingFor(m.watchTarget()); h.forceFlag != "" || h.interactive {
+		m.statusErr = true
+		m.statusText = "not watching: the command asks for confirmation or input on every run"
+		return m, nil
+	}
+	secs := 5
+	if m.watchEvery > 0 {
+		secs = int(m.watchEvery / time.Second)
+	}
+	return m.openPrompt(promptWatch, "watch every (seconds)", strconv.Itoa(secs))
+}
+
+// startWatch begins re-running the watch target every interval seconds.
+func (m model) startWatch(interval string) (tea.Model, tea.Cmd) {
+	secs, err := strconv.Atoi(strings.TrimSpace(interval))
+	if err != nil || secs < 1 {
+		m.statusErr = true
+		m.statusText = "watch interval must be a whole number of seconds (1 or more)"
+		return m, nil
+	}
+	m.watching = true
+	m.watchParts = m.watchTarget()
+	m.watchEvery = time.Duration(secs) * time.Second
+	m.watchIter = 1
+	m.watchGen++
+	m.lastCmd = m.watchParts
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("watching every %ds (any key stops)", secs)
+	return m, m.runCommandCmd(m.watchParts)
+}
+
+func (m model) stopWatch() model {
+	m.watching = false
+	m.watchGen++
+	m.statusErr = false
+	m.statusText = fmt.Sprintf("watch stopped after %d run(s)", m.watchIter)
+	return m
+}
+
+func (m model) watchTickCmd() tea.Cmd {
+	gen := m.watchGen
+	return tea.Tick(m.watchEvery, func(time.Time) tea.Msg { return watchTickMsg{gen: gen} })
+}
+
+func (m model) onWatchTick(msg watchTickMsg) (tea.Model, tea.Cmd) {
+	if !m.watching || msg.gen != m.watchGen {
+		return m, nil
+	}
+	if m.running {
+		return m, m.watchTickCmd()
+	}
+	m.watchIter++
+	return m, m.runCommandCmd(m.watchParts)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	_ "embed"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+//go:embed CHANGELOG.md
+var changelog string
+
+// whatsNew returns the changelog sections above the heading for lastSeen,
+// or only the newest section when lastSeen is not listed.
+func whatsNew(changelog, lastSeen string) string {
+	var sections []string
+	for _, part := range strings.Split(changelog, "\n## ")[1:] {
+		heading, _, _ := strings.Cut(part, "\n")
+		if f := strings.Fields(heading); len(f) > 0 && f[0] == lastSeen {
+			return strings.Join(sections, "\n\n")
+		}
+		sections = append(sections, "## "+strings.TrimSpace(part))
+	}
+	if len(sections) == 0 {
+		return ""
+	}
+	return sections[0]
+}
+
+// checkWhatsNew decides at startup whether to show what changed since the
+// last version run. Fresh installs and dev builds only record the version.
+func (m model) checkWhatsNew() model {
+	if version == "dev" || m.state.LastSeenVersion == version {
+		return m
+	}
+	if m.state.LastSeenVersion == "" {
+		m.state.LastSeenVersion = version
+		_ = saveState(m.state)
+		return m
+	}
+	m.whatsNew = whatsNew(changelog, m.state.LastSeenVersion)
+	if m.whatsNew == "" {
+		return m.dismissWhatsNew()
+	}
+	return m
+}
+
+// dismissWhatsNew closes the overlay and remembers the current version so
+// it is not shown again until the next upgrade.
+func (m model) dismissWhatsNew() model {
+	m.whatsNew = ""
+	m.state.LastSeenVersion = version
+	if err := saveState(m.state); err != nil {
+		m.statusErr = true
+		m.statusText = "could not save state: " + err.Error()
+	}
+	return m
+}
+
+func (m model) updateWhatsNew(tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.dismissWhatsNew(), nil
+}
+
+func (m model) renderWhatsNew(width, height int) string {
+	var b strings.Builder
+	b.WriteString(m.styles.paneTitle.Render("What's new in devtunnel-tui " + version))
+	b.WriteString("\n\n")
+	b.WriteString(m.whatsNew)
+	return m.styles.pane.Width(width).Height(height).BorderForeground(lipgloss.Color("39")).Render(b.String())
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wizardField is one step of a guided form. A field with choices is picked
+// with ←/→ instead of typed. args turns the value into argv; an empty
+// result leaves the setting at the CLI default.
+type wizardField struct {
+	label    string
+	hint     string
+	def      string
+	choices  []string
+	validate func(string) error
+	args     func(string) []string
+}
+
+// wizardRun is an open wizard. step == len(fields) is the summary.
+type wizardRun struct {
+	cmd    commandItem
+	values []string
+	step   int
+}
+
+var (
+	validTunnelIDRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,58}[a-z0-9]$`)
+	labelRe         = regexp.MustCompile(`^[A-Za-z0-9_.=-]{1,50}$`)
+)
+
+// createWizard walks through the settings most tunnels need. Everything
+// but the access choice may be left empty.
+func createWizard() []wizardField {
+	return []wizardField{
+		{
+			label: "tunnel id",
+			hint:  "lowercase letters, digits and hyphens; leave empty to have one generated",
+			validate: func(v string) error {
+				if v != "" && !validTunnelIDRe.MatchString(v) {
+					return fmt.Errorf("use 3-60 lowercase letters, digits or hyphens, not starting or ending with a hyphen")
+				}
+				return nil
+			},
+			args: optionalArg(""),
+		},
+		{
+			label: "description",
+			hint:  "shown in `devtunnel list`; optional",
+			validate: func(v string) error {
+				if len(v) > 400 {
+					return fmt.Errorf("at most 400 characters")
+				}
+				return nil
+			},
+			args: optionalArg("--description"),
+		},
+		{
+			label: "labels",
+			hint:  "comma or space separated, e.g. web, staging; optional",
+			validate: func(v string) error {
+				for _, l := range splitLabels(v) {
+					if !labelRe.MatchString(l) {
+						return fmt.Errorf("%q: use up to 50 letters, digits, '-', '_', '.' or '='", l)
+					}
+				}
+				return nil
+			},
+			args: func(v string) []string {
+				var out []string
+				for _, l := range splitLabels(v) {
+					out = append(out, "--labels", l)
+				}
+				return out
+			},
+		},
+		{
+			label:   "access",
+			hint:    "anonymous lets anyone with the URL connect without signing in",
+			def:     "private",
+			choices: []string{"private", "anonymous"},
+			args: func(v string) []string {
+				if v == "anonymous" {
+					return []string{"--allow-anonymous"}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// optionalArg passes a non-empty value after flag, or positionally when
+// flag is empty.
+func optionalArg(flag string) func(string) []string {
+	return func(v string) []string {
+		switch {
+		case v == "":
+			return nil
+		case flag == "":
+			return []string{v}
+		}
+		return []string{flag, v}
+	}
+}
+
+func splitLabels(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// openWizard starts cmd's guided form with every field at its default.
+func (m model) openWizard(cmd commandItem) (tea.Model, tea.Cmd) {
+	w := &wizardRun{cmd: cmd, values: make([]string, len(cmd.wizard))}
+	for i, f := range cmd.wizard {
+		w.values[i] = f.def
+	}
+	m.wizard = w
+	return m.wizardStep(0)
+}
+
+// wizardStep shows step i, loading its saved value into the input.
+func (m model) wizardStep(i int) (model, tea.Cmd) {
+	w := m.wizard
+	w.step = i
+	m.wizardInput.Blur()
+	if i >= len(w.cmd.wizard) || w.cmd.wizard[i].choices != nil {
+		return m, nil
+	}
+	m.wizardInput.Prompt = "> "
+	m = m.resizeInputs()
+	m.wizardInput.SetValue(w.values[i])
+	m.wizardInput.CursorEnd()
+	m.wizardInput.Focus()
+	return m, textinput.Blink
+}
+
+// wizardArgs assembles the command from the values entered so far.
+func (m model) wizardArgs() []sourcedArg {
+	w := m.wizard
+	args := commandArgs(&w.cmd)
+	for i, f := range w.cmd.wizard {
+		for _, a := range f.args(w.values[i]) {
+			args = append(args, sourcedArg{value: a, source: f.label})
+		}
+	}
+	return m.withClusterArgs(&w.cmd, args)
+}
+
+func (m model) updateWizard(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	w := m.wizard
+	if k.String() == "esc" {
+		m.wizard = nil
+		m.wizardInput.Blur()
+		m.nextBackground = false
+		m.statusErr = false
+		m.statusText = "cancelled"
+		return m, nil
+	}
+	if w.step == len(w.cmd.wizard) {
+		switch k.String() {
+		case "enter":
+			parts := argValues(m.wizardArgs())
+			m.wizard = nil
+			m.lastCmd = parts
+			return m.runCached(parts)
+		case "shift+tab", "up":
+			return m.wizardStep(w.step - 1)
+		}
+		return m, nil
+	}
+
+	f := w.cmd.wizard[w.step]
+	if f.choices != nil {
+		i := slices.Index(f.choices, w.values[w.step])
+		switch k.String() {
+		case "left", "h":
+			w.values[w.step] = f.choices[max(0, i-1)]
+			return m, nil
+		case "right", "l":
+			w.values[w.step] = f.choices[min(len(f.choices)-1, i+1)]
+			return m, nil
+		}
+	} else {
+		w.values[w.step] = strings.TrimSpace(m.wizardInput.Value())
+	}
+
+	switch k.String() {
+	case "shift+tab", "up":
+		if w.step > 0 {
+			return m.wizardStep(w.step - 1)
+		}
+		return m, nil
+	case "enter", "tab", "down":
+		if f.validate != nil {
+			if err := f.validate(w.values[w.step]); err != nil {
+				m.statusErr = true
+				m.statusText = "invalid " + f.label + ": " + err.Error()
+				return m, nil
+			}
+		}
+		m.statusErr = false
+		m.statusText = w.cmd.name
+		return m.wizardStep(w.step + 1)
+	}
+	if f.choices != nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.wizardInput, cmd = m.wizardInput.Update(k)
+	return m, cmd
+}
+
+func (m model) renderWizard() string {
+	w := m.wizard
+	var b strings.Builder
+	if w.step == len(w.cmd.wizard) {
+		b.WriteString(m.styles.warn.Render(w.cmd.name + ": summary"))
+		b.WriteString("\n")
+		for i, f := range w.cmd.wizard {
+			v := w.values[i]
+			if v == "" {
+				v = m.styles.dim.Render("(default)")
+			}
+			fmt.Fprintf(&b, "  %-12s %s\n", f.label, v)
+		}
+		b.WriteString("$ " + m.secrets.displayCmd(argValues(m.wizardArgs())))
+		b.WriteString("\n")
+		b.WriteString("Enter run, Shift+Tab back, Esc cancel")
+		return m.styles.cmdline.Render(b.String())
+	}
+
+	f := w.cmd.wizard[w.step]
+	b.WriteString(fmt.Sprintf("%s - step %d/%d: %s", w.cmd.name, w.step+1, len(w.cmd.wizard), f.label))
+	b.WriteString("\n")
+	if f.choices != nil {
+		items := make([]string, len(f.choices))
+		for i, c := range f.choices {
+			if c == w.values[w.step] {
+				items[i] = m.styles.hotkey.Render("(*) " + c)
+			} else {
+				items[i] = "( ) " + c
+			}
+		}
+		b.WriteString(strings.Join(items, "  "))
+	} else {
+		b.WriteString(m.wizardInput.View())
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.dim.Render(f.hint))
+	b.WriteString("\n")
+	b.WriteString("Enter next, Shift+Tab back, Esc cancel")
+	return m.styles.cmdline.Render(b.String())
+}
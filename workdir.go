@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/' && rest[0] != filepath.Separator) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// resolveWorkDir expands a leading ~ and makes dir absolute, checking that
+// it names an existing directory. An empty dir means the current one.
+func resolveWorkDir(dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", nil
+	}
+	dir, err := expandHome(dir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", abs)
+	}
+	return abs, nil
+}
+
+// setWorkDir changes the directory commands run in, keeping the old one
+// when the new path is invalid.
+func (m model) setWorkDir(dir string) model {
+	abs, err := resolveWorkDir(dir)
+	if err != nil {
+		m.statusErr = true
+		m.statusText = "working dir unchanged: " + err.Error()
+		return m
+	}
+	m.workDir = abs
+	m.statusErr = false
+	m.statusText = "working dir: " + m.workDirLabel()
+	return m
+}
+
+// workDirLabel is the active working directory for display.
+func (m model) workDirLabel() string {
+	if m.workDir != "" {
+		return m.workDir
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return wd
+	}
+	return "."
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type workflowConfig struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Steps       []string `json:"steps"`
+}
+
+type workflowRun struct {
+	name   string
+	steps  [][]string
+	step   int
+	start  time.Time
+	output strings.Builder
+}
+
+var placeholderRe = regexp.MustCompile(`\{([A-Za-z0-9_-]+)\}`)
+
+// workflowParams returns the placeholder names used across all steps, in
+// order of first appearance.
+func workflowParams(steps []string) []string {
+	seen := map[string]bool{}
+	var params []string
+	for _, step := range steps {
+		for _, match := range placeholderRe.FindAllStringSubmatch(step, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				params = append(params, match[1])
+			}
+		}
+	}
+	return params
+}
+
+// workflowCategory builds the Workflows category from config, returning
+// warnings for entries that were skipped.
+func workflowCategory(workflows []workflowConfig) (commandCategory, []string) {
+	cat := commandCategory{name: "Workflows"}
+	var warnings []string
+	for i, wf := range workflows {
+		if strings.TrimSpace(wf.Name) == "" || len(wf.Steps) == 0 {
+			warnings = append(warnings, fmt.Sprintf("workflow #%d skipped: name and steps are required", i+1))
+			continue
+		}
+		wf := wf
+		desc := wf.Description
+		if desc == "" {
+			desc = fmt.Sprintf("Workflow (%d steps)", len(wf.Steps))
+		}
+		cat.commands = append(cat.commands, commandItem{
+			name:        wf.Name,
+			description: desc,
+			baseArgs:    []string{},
+			required:    workflowParams(wf.Steps),
+			workflow:    &wf,
+		})
+	}
+	return cat, warnings
+}
+
+// expandWorkflow substitutes params into each step. Steps are split with
+// shell-style quoting before substitution so values containing spaces stay
+// one argument.
+func expandWorkflow(wf *workflowConfig, params map[string]string) [][]string {
+	steps := make([][]string, 0, len(wf.Steps))
+	for _, step := range wf.Steps {
+		parts := []string{"devtunnel"}
+		for _, field := range splitArgs(step) {
+			parts = append(parts, fillPlaceholders(field, params))
+		}
+		steps = append(steps, parts)
+	}
+	return steps
+}
+
+// fillPlaceholders replaces each {name} in s with params[name].
+func fillPlaceholders(s string, params map[string]string) string {
+	return placeholderRe.ReplaceAllStringFunc(s, func(ph string) string {
+		return params[ph[1:len(ph)-1]]
+	})
+}
+
+func (m model) startWorkflow(wf *workflowConfig, params map[string]string) (tea.Model, tea.Cmd) {
+	return m.startSequence(wf.Name, expandWorkflow(wf, params))
+}
+
+// advanceWorkflow records a finished step and either starts the next one or
+// ends the workflow, stopping at the first failure.
+func (m model) advanceWorkflow(msg runFinishedMsg) (tea.Model, tea.Cmd) {
+	wf := m.workflow
+	fmt.Fprintf(&wf.output, "$ %s\n\n%s\n", msg.cmdText, msg.output)
+
+	total := len(wf.steps)
+	if msg.err == nil && wf.step < total-1 {
+		wf.step++
+		return m, m.runCommandCmd(wf.steps[wf.step])
+	}
+
+	m.running = false
+	m.workflow = nil
+	m.lastOutput = wf.output.String()
+	m.haveStreams = false
+	m.outStream = streamCombined
+	m.lastText = "workflow " + wf.name
+	m.cachedAt = time.Time{}
+	if msg.err != nil {
+		m.statusErr = true
+		status, _ := describeFailure(m.procs, msg.args, msg.err)
+		m.statusText = fmt.Sprintf("workflow %s failed at step %d/%d: %s", wf.name, wf.step+1, total, status)
+	} else {
+		m.statusErr = false
+		m.statusText = fmt.Sprintf("workflow %s completed", wf.name)
+	}
+	m = m.setOutput(m.lastOutput)
+	m.viewport.GotoBottom()
+	title := "ready"
+	if msg.err != nil {
+		title = "workflow " + wf.name + " failed"
+	}
+	return m, tea.Batch(notifyCmd(m.cfg.Notify, "workflow "+wf.name, msg.err != nil, time.Since(wf.start)), m.titleCmd(title))
+}